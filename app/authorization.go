@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+// Roles of a principal for a company, each granting the permissions of the previous ones.
+// Readers may not write metadata, writers may create metadata and update or delete the metadata they
+// maintain, and admins may update or delete any metadata
+const (
+	RoleReader = "reader"
+	RoleWriter = "writer"
+	RoleAdmin  = "admin"
+)
+
+var roleRanks = map[string]int{RoleReader: 1, RoleWriter: 2, RoleAdmin: 3}
+
+// RoleRank orders the roles by the permissions they grant, 0 for unknown roles
+func RoleRank(role string) int {
+	return roleRanks[role]
+}
+
+// RoleProvider returns the role of principals per company
+type RoleProvider interface {
+	Role(principal Principal, company string) string
+}
+
+// authorizeCreate checks that the principal may create the metadata
+func (m MetadataManager) authorizeCreate(principal Principal, metadata *models.Metadata) error {
+	return m.requireRole(principal, metadata.Company, RoleWriter)
+}
+
+// authorizeChange checks that the principal may update or delete the current metadata, and that it may write
+// the updated metadata, if any, in case it moves to another company
+func (m MetadataManager) authorizeChange(principal Principal, id string, current *models.Metadata, updated *models.Metadata) error {
+	if m.role(principal, current.Company) != RoleAdmin {
+		if err := m.requireRole(principal, current.Company, RoleWriter); err != nil {
+			return err
+		}
+		if !isMaintainer(principal, current) {
+			if principal.Email == "" {
+				return ForbiddenError{Reason: fmt.Sprintf("%s has no email and is not an admin of %s", principal.Subject, current.Company)}
+			}
+			return ForbiddenError{Reason: fmt.Sprintf("%s is neither a maintainer of metadata %s nor an admin of %s",
+				principal.Email, id, current.Company)}
+		}
+	}
+	if updated != nil && !strings.EqualFold(updated.Company, current.Company) {
+		return m.requireRole(principal, updated.Company, RoleWriter)
+	}
+	return nil
+}
+
+func (m MetadataManager) requireRole(principal Principal, company string, role string) error {
+	actual := m.role(principal, company)
+	if RoleRank(actual) >= RoleRank(role) {
+		return nil
+	}
+	if actual == "" {
+		return ForbiddenError{Reason: fmt.Sprintf("%s has no role for %s, %s role required", principal.Subject, company, role)}
+	}
+	return ForbiddenError{Reason: fmt.Sprintf("%s is a %s of %s, %s role required", principal.Subject, actual, company, role)}
+}
+
+// role returns the role of the principal for the company; every principal is a writer when no roles are set
+func (m MetadataManager) role(principal Principal, company string) string {
+	if m.roles == nil {
+		return RoleWriter
+	}
+	return m.roles.Role(principal, company)
+}
+
+// isMaintainer returns whether the email of the principal is one of the maintainers of the metadata
+func isMaintainer(principal Principal, metadata *models.Metadata) bool {
+	if principal.Email == "" {
+		return false
+	}
+	email := NormalizeEmail(principal.Email)
+	for _, maintainer := range metadata.Maintainers {
+		if maintainer != nil && NormalizeEmail(maintainer.Email) == email {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeEmail returns the lowercased address of an email, e.g. erik.wu@microsoft.com for
+// "Erik Wu <Erik.Wu@microsoft.com>"
+func NormalizeEmail(email string) string {
+	if address, err := mail.ParseAddress(email); err == nil {
+		email = address.Address
+	}
+	return strings.ToLower(strings.TrimSpace(email))
+}
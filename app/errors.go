@@ -5,15 +5,141 @@ import (
 	"strings"
 )
 
+// Severities of validation errors. Warnings are advisory and do not prevent a write
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
 type ValidationError struct {
+	// Path is a JSON pointer (RFC 6901) to the invalid value, e.g. /maintainers/2/email
 	Path   string
 	Reason string
+	// Rule is the id of the validation rule that failed, if any
+	Rule string
+	// Line and Column locate the invalid value in the original document, 0 if unknown
+	Line   int
+	Column int
+	// Severity is either SeverityError or SeverityWarning; empty means SeverityError
+	Severity string
+}
+
+// IsWarning returns whether the error is only advisory
+func (e ValidationError) IsWarning() bool {
+	return e.Severity == SeverityWarning
 }
 
 func (e ValidationError) Error() string {
 	return fmt.Sprintf("Failed to validate %s due to %s", e.Path, e.Reason)
 }
 
+func (e ValidationError) Code() string {
+	return CodeValidationFailed
+}
+
+// Pointer builds a JSON pointer from the keys and indexes of a path, e.g. Pointer("maintainers", 2, "email")
+// returns /maintainers/2/email
+func Pointer(segments ...interface{}) string {
+	sb := strings.Builder{}
+	for _, segment := range segments {
+		sb.WriteString("/")
+		sb.WriteString(pointerEscaper.Replace(fmt.Sprint(segment)))
+	}
+	return sb.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Error codes identify the kind of an error in a stable, machine-readable way
+const (
+	CodeValidationFailed   = "validation_failed"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodePreconditionFailed = "precondition_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeInternal           = "internal"
+)
+
+// CodedError is implemented by the errors of the taxonomy below
+type CodedError interface {
+	error
+	Code() string
+}
+
+// ErrorCode returns the code of the error, CodeInternal for errors outside of the taxonomy
+func ErrorCode(err error) string {
+	if coded, k := err.(CodedError); k {
+		return coded.Code()
+	}
+	return CodeInternal
+}
+
+// NotFoundError is returned when the requested metadata or revision does not exist
+type NotFoundError struct {
+	Reason string
+}
+
+func (e NotFoundError) Error() string {
+	return e.Reason
+}
+
+func (e NotFoundError) Code() string {
+	return CodeNotFound
+}
+
+// ConflictError is returned when a write conflicts with the constraints of the stored data
+type ConflictError struct {
+	Reason string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("Conflict due to %s", e.Reason)
+}
+
+func (e ConflictError) Code() string {
+	return CodeConflict
+}
+
+// PreconditionFailedError is returned when a conditional write does not match the stored revision
+type PreconditionFailedError struct {
+	Reason string
+}
+
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf("Precondition failed due to %s", e.Reason)
+}
+
+func (e PreconditionFailedError) Code() string {
+	return CodePreconditionFailed
+}
+
+// UnauthorizedError is returned when a request does not carry valid credentials
+type UnauthorizedError struct {
+	Reason string
+}
+
+func (e UnauthorizedError) Error() string {
+	return fmt.Sprintf("Unauthorized due to %s", e.Reason)
+}
+
+func (e UnauthorizedError) Code() string {
+	return CodeUnauthorized
+}
+
+// ForbiddenError is returned when the principal of a request is not allowed to perform it
+type ForbiddenError struct {
+	Reason string
+}
+
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("Forbidden due to %s", e.Reason)
+}
+
+func (e ForbiddenError) Code() string {
+	return CodeForbidden
+}
+
 type AggregatedValidationError struct {
 	errors []ValidationError
 }
@@ -35,6 +161,26 @@ func (e AggregatedValidationError) Errors() []ValidationError {
 	return e.errors
 }
 
+// Warnings returns the advisory errors
+func (e AggregatedValidationError) Warnings() []ValidationError {
+	warnings := make([]ValidationError, 0)
+	for _, err := range e.errors {
+		if err.IsWarning() {
+			warnings = append(warnings, err)
+		}
+	}
+	return warnings
+}
+
+// HasErrors returns whether any of the errors is not a warning
+func (e AggregatedValidationError) HasErrors() bool {
+	return len(e.Warnings()) != len(e.errors)
+}
+
+func (e AggregatedValidationError) Code() string {
+	return CodeValidationFailed
+}
+
 func (e AggregatedValidationError) Error() string {
 	sb := strings.Builder{}
 	if e.errors == nil {
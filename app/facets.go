@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// facetFields maps the field names that can be aggregated, which are the lowercased canonical names,
+// to their canonical names
+var facetFields = map[string]string{
+	"title":   "Title",
+	"company": "Company",
+	"license": "License",
+	// maintainer emails
+	"email": "Email",
+}
+
+// getFacetFields parses a comma separated list of fields to aggregate and returns their canonical names,
+// without duplicates
+func getFacetFields(fields string) ([]string, error) {
+	result := make([]string, 0)
+	if strings.TrimSpace(fields) == "" {
+		return nil, ValidationError{Path: Pointer("fields"), Reason: "empty value"}
+	}
+	requested := make(map[string]bool)
+	for _, name := range strings.Split(fields, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		field, k := facetFields[name]
+		if !k {
+			return nil, ValidationError{Path: Pointer("fields"), Reason: fmt.Sprintf("cannot aggregate %s", name)}
+		}
+		if !requested[field] {
+			requested[field] = true
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
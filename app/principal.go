@@ -0,0 +1,34 @@
+package app
+
+import "context"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject identifies the caller, e.g. the name of an API key or the sub claim of a token
+	Subject string
+	// Email of the caller, empty if unknown
+	Email string
+	// Method is the authentication method, e.g. api-key or bearer
+	Method string
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of the context carrying the principal
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by the context, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, k := ctx.Value(principalKey{}).(Principal)
+	return principal, k
+}
+
+// caller describes the principal of the context for logs
+func caller(ctx context.Context) string {
+	if principal, k := PrincipalFromContext(ctx); k {
+		return principal.Subject
+	}
+	return "anonymous"
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+// DefaultSearchLimit is the page size of searches that do not set a limit
+const DefaultSearchLimit = 100
+
+// MaxSearchLimit is the largest page size a search may ask for
+const MaxSearchLimit = 1000
+
+// Fields results can be sorted by
+const (
+	SortRelevance = "relevance"
+	SortTitle     = "title"
+	SortCompany   = "company"
+	SortVersion   = "version"
+	SortCreated   = "created"
+	SortUpdated   = "updated"
+)
+
+// matchableFields maps the field names accepted in match modes to their canonical names
+var matchableFields = map[string]string{
+	"title":   "Title",
+	"company": "Company",
+	"website": "Website",
+	"source":  "Source",
+	"license": "License",
+	// maintainer names
+	"name": "Name",
+}
+
+func getFieldMatches(matches map[string]models.FieldMatch) (map[string]models.FieldMatch, error) {
+	aggregateErr := NewAggregatedValidationError()
+	result := make(map[string]models.FieldMatch, len(matches))
+	for name, match := range matches {
+		field, k := matchableFields[strings.ToLower(name)]
+		if !k {
+			aggregateErr.AddError(ValidationError{Path: Pointer("match", name), Reason: "field does not support match modes"})
+			continue
+		}
+		if match.Mode == "" {
+			match.Mode = models.MatchExact
+		}
+		switch {
+		case match.Mode == models.MatchFuzzy:
+			if field != "Title" && field != "Company" && field != "Name" {
+				aggregateErr.AddError(ValidationError{Path: Pointer("match", name), Reason: "fuzzy matching is only supported on title, company and name"})
+				continue
+			}
+			if match.MaxDistance < 0 {
+				aggregateErr.AddError(ValidationError{Path: Pointer("match", name, "maxDistance"), Reason: "negative value"})
+				continue
+			}
+			if match.MaxDistance == 0 {
+				match.MaxDistance = models.DefaultMaxDistance
+			}
+			result[field] = match
+		case field == "Name":
+			aggregateErr.AddError(ValidationError{Path: Pointer("match", name), Reason: "maintainer names only support fuzzy matching"})
+		case match.Mode == models.MatchExact, match.Mode == models.MatchPrefix, match.Mode == models.MatchGlob:
+			result[field] = match
+		default:
+			aggregateErr.AddError(ValidationError{Path: Pointer("match", name), Reason: fmt.Sprintf("unknown match mode %s", match.Mode)})
+		}
+	}
+	if len(aggregateErr.Errors()) != 0 {
+		return nil, aggregateErr
+	}
+	return result, nil
+}
+
+// getSortKeys parses a comma separated list of sort fields, each optionally prefixed with - to sort
+// in descending order
+func getSortKeys(sort string) ([]SortKey, error) {
+	keys := make([]SortKey, 0)
+	if strings.TrimSpace(sort) == "" {
+		return keys, nil
+	}
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		key := SortKey{Field: strings.TrimPrefix(field, "-"), Descending: strings.HasPrefix(field, "-")}
+		switch key.Field {
+		case SortRelevance, SortTitle, SortCompany, SortVersion, SortCreated, SortUpdated:
+			keys = append(keys, key)
+		default:
+			return nil, ValidationError{Path: Pointer("sort"), Reason: fmt.Sprintf("cannot sort by %s", field)}
+		}
+	}
+	return keys, nil
+}
+
+// getPage returns the offset and limit of a search, the offset being given either directly or by a cursor
+func getPage(request models.SearchRequest) (offset int, limit int, err error) {
+	aggregateErr := NewAggregatedValidationError()
+	offset = request.Offset
+	if offset < 0 {
+		aggregateErr.AddError(ValidationError{Path: Pointer("offset"), Reason: "negative value"})
+	}
+	if request.Cursor != "" {
+		if request.Offset != 0 {
+			aggregateErr.AddError(ValidationError{Path: Pointer("cursor"), Reason: "cursor and offset are mutually exclusive"})
+		} else if offset, err = decodeCursor(request.Cursor); err != nil {
+			aggregateErr.AddError(ValidationError{Path: Pointer("cursor"), Reason: "invalid cursor"})
+		}
+	}
+	limit = request.Limit
+	switch {
+	case limit == 0:
+		limit = DefaultSearchLimit
+	case limit < 0 || limit > MaxSearchLimit:
+		aggregateErr.AddError(ValidationError{Path: Pointer("limit"), Reason: fmt.Sprintf("must be between 1 and %d", MaxSearchLimit)})
+	}
+	if len(aggregateErr.Errors()) != 0 {
+		return 0, 0, aggregateErr
+	}
+	return offset, limit, nil
+}
+
+// cursors are opaque to clients
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimPrefix(string(decoded), "offset:")
+	if value == string(decoded) {
+		return 0, fmt.Errorf("invalid cursor %s", cursor)
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %s", cursor)
+	}
+	return offset, nil
+}
@@ -1,54 +1,262 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/query"
 	"log"
+	"strings"
 )
 
+// MetadataManager implements the use cases of the catalog. Methods take the context of the request, which
+// carries the authenticated Principal, if any; writes are logged along with their principal
 type MetadataManager struct {
 	repository MetadataRepository
 	validator  MetadataValidator
 	logger     *log.Logger
+	// roles of the principals; every principal is a writer if nil
+	roles RoleProvider
 }
 
-func (m MetadataManager) CreateMetadata(metadata models.Metadata) (uuid.UUID, error) {
+// CreateMetadata stores the metadata, provided that the principal of the context, if any, may create it,
+// and returns its id along with the validation warnings, if any
+func (m MetadataManager) CreateMetadata(ctx context.Context, metadata models.Metadata) (uuid.UUID, []ValidationError, error) {
 	var id uuid.UUID
-	if err := m.validator.ValidateAndSanitize(&metadata); err != nil {
-		return id, err
+	warnings, err := m.validate(&metadata)
+	if err != nil {
+		return id, nil, err
+	}
+	if principal, k := PrincipalFromContext(ctx); k {
+		if err := m.authorizeCreate(principal, &metadata); err != nil {
+			return id, nil, err
+		}
 	}
-	id, err := m.repository.Insert(&metadata)
+	id, err = m.repository.Insert(&metadata, caller(ctx))
 	if err != nil {
-		return id, err
+		return id, nil, err
 	}
-	return id, nil
+	m.logger.Printf("metadata %s created by %s", id.String(), caller(ctx))
+	return id, warnings, nil
 }
 
-func (m MetadataManager) UpdateMetadata(metadata models.Metadata, id uuid.UUID) error {
-	if err := m.validator.ValidateAndSanitize(&metadata); err != nil {
-		return err
+// UpdateMetadata updates the metadata if expectedRevision is 0 or matches its current revision, provided that
+// the principal of the context, if any, may change it, and returns the new revision along with the validation
+// warnings, if any
+func (m MetadataManager) UpdateMetadata(ctx context.Context, metadata models.Metadata, id uuid.UUID, expectedRevision int) (int, []ValidationError, error) {
+	warnings, err := m.validate(&metadata)
+	if err != nil {
+		return 0, nil, err
+	}
+	revision, err := m.update(ctx, &metadata, id, expectedRevision)
+	if err != nil {
+		return 0, nil, err
+	}
+	m.logger.Printf("metadata %s updated to revision %d by %s", id.String(), revision, caller(ctx))
+	return revision, warnings, nil
+}
+
+// ValidateMetadata validates and sanitizes the metadata without storing it, and returns the sanitized metadata
+// along with the validation warnings, if any
+func (m MetadataManager) ValidateMetadata(ctx context.Context, metadata models.Metadata) (models.Metadata, []ValidationError, error) {
+	warnings, err := m.validate(&metadata)
+	if err != nil {
+		return models.Metadata{}, nil, err
+	}
+	return metadata, warnings, nil
+}
+
+// validate validates and sanitizes the metadata. Warnings are returned separately unless the metadata
+// has errors as well, in which case all of them are returned as the error
+func (m MetadataManager) validate(metadata *models.Metadata) ([]ValidationError, error) {
+	err := m.validator.ValidateAndSanitize(metadata)
+	if err == nil {
+		return nil, nil
+	}
+	switch e := err.(type) {
+	case AggregatedValidationError:
+		if e.HasErrors() {
+			return nil, err
+		}
+		return e.Warnings(), nil
+	case ValidationError:
+		if e.IsWarning() {
+			return []ValidationError{e}, nil
+		}
 	}
-	if err := m.repository.Update(id, &metadata); err != nil {
+	return nil, err
+}
+
+// update replaces the metadata once change authorizes it
+func (m MetadataManager) update(ctx context.Context, metadata *models.Metadata, id uuid.UUID, expectedRevision int) (int, error) {
+	return m.change(ctx, id, metadata, expectedRevision, func(revision int) (int, error) {
+		return m.repository.Update(id, metadata, revision, caller(ctx))
+	})
+}
+
+// change authorizes an update, or a deletion if updated is nil, against the current metadata when the context
+// carries a principal. The write is then conditional on the authorized revision, so that the maintainers cannot
+// change in between, and is attempted again if they might have, unless the caller expected a given revision
+func (m MetadataManager) change(ctx context.Context, id uuid.UUID, updated *models.Metadata, expectedRevision int,
+	write func(expectedRevision int) (int, error)) (int, error) {
+	principal, k := PrincipalFromContext(ctx)
+	if !k {
+		return write(expectedRevision)
+	}
+	for attempt := 1; ; attempt++ {
+		current, err := m.repository.GetCurrentRevision(id)
+		if err != nil {
+			return 0, err
+		}
+		if err := m.authorizeChange(principal, id.String(), &current.Metadata, updated); err != nil {
+			return 0, err
+		}
+		authorizedRevision := expectedRevision
+		if authorizedRevision == 0 {
+			authorizedRevision = current.Number
+		}
+		revision, err := write(authorizedRevision)
+		if _, conflict := err.(PreconditionFailedError); conflict && expectedRevision == 0 && attempt < maxChangeAttempts {
+			continue
+		}
+		return revision, err
+	}
+}
+
+// maxChangeAttempts bounds the number of times an authorized write is attempted under concurrent updates
+const maxChangeAttempts = 3
+
+// DeleteMetadata deletes the metadata, provided that the principal of the context, if any, may change it
+func (m MetadataManager) DeleteMetadata(ctx context.Context, id uuid.UUID) error {
+	_, err := m.change(ctx, id, nil, 0, func(revision int) (int, error) {
+		return 0, m.repository.Delete(id, revision, caller(ctx))
+	})
+	if err != nil {
 		return err
 	}
+	m.logger.Printf("metadata %s deleted by %s", id.String(), caller(ctx))
 	return nil
 }
 
-func (m MetadataManager) SearchMetadata(metadata models.Metadata) (models.SearchResults, error) {
-	if err := m.validator.SanitizeURLs(&metadata); err != nil {
+func (m MetadataManager) SearchMetadata(ctx context.Context, request models.SearchRequest) (models.SearchResults, error) {
+	criteria, err := m.getSearchCriteria(request)
+	if err != nil {
 		return models.SearchResults{}, err
 	}
-	results, err := m.repository.Search(&metadata)
+	sortKeys, err := getSortKeys(request.Sort)
 	if err != nil {
 		return models.SearchResults{}, err
 	}
-	searchResults := models.SearchResults{Results: results}
+	offset, limit, err := getPage(request)
+	if err != nil {
+		return models.SearchResults{}, err
+	}
+	criteria.Sort, criteria.Offset, criteria.Limit = sortKeys, offset, limit
+	results, total, err := m.repository.Search(criteria)
+	if err != nil {
+		return models.SearchResults{}, err
+	}
+	searchResults := models.SearchResults{Results: results, Total: total}
+	if offset+len(results) < total {
+		searchResults.Next = encodeCursor(offset + len(results))
+	}
 	return searchResults, nil
 }
 
-func (m MetadataManager) GetMetadata(id uuid.UUID) (*models.Metadata, error) {
-	return m.repository.Get(id)
+// GetFacets counts the metadata matching the search per value of each of the comma separated fields.
+// An empty search aggregates the whole catalog
+func (m MetadataManager) GetFacets(ctx context.Context, fields string, request models.SearchRequest) (models.FacetResults, error) {
+	facetFields, err := getFacetFields(fields)
+	if err != nil {
+		return models.FacetResults{}, err
+	}
+	criteria, err := m.getSearchCriteria(request)
+	if err != nil {
+		return models.FacetResults{}, err
+	}
+	facets, total, err := m.repository.Facets(criteria, facetFields)
+	if err != nil {
+		return models.FacetResults{}, err
+	}
+	// facets are returned under the lowercased names they are requested with
+	results := models.FacetResults{Total: total, Facets: make(map[string][]models.FacetValue, len(facetFields))}
+	for _, field := range facetFields {
+		results.Facets[strings.ToLower(field)] = facets[field]
+	}
+	return results, nil
+}
+
+// getSearchCriteria validates the filters of a search
+func (m MetadataManager) getSearchCriteria(request models.SearchRequest) (SearchCriteria, error) {
+	metadata := request.Metadata
+	if err := m.validator.SanitizeURLs(&metadata); err != nil {
+		return SearchCriteria{}, err
+	}
+	expr, err := parseQuery(request.Query, "query")
+	if err != nil {
+		return SearchCriteria{}, err
+	}
+	parameterExpr, err := parseQuery(request.QueryParameter, "q")
+	if err != nil {
+		return SearchCriteria{}, err
+	}
+	// both expressions are parsed on their own so that neither can escape into the other
+	if expr == nil {
+		expr = parameterExpr
+	} else if parameterExpr != nil {
+		expr = query.And{Left: expr, Right: parameterExpr}
+	}
+	match, err := getFieldMatches(request.Match)
+	if err != nil {
+		return SearchCriteria{}, err
+	}
+	return SearchCriteria{Metadata: &metadata, Expr: expr, Match: match}, nil
+}
+
+// parseQuery parses a query expression, syntax errors being reported at the given field.
+// A blank expression is no expression
+func parseQuery(input string, field string) (query.Expr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	expr, err := query.Parse(input)
+	if syntaxErr, k := err.(query.SyntaxError); k {
+		return nil, ValidationError{Path: Pointer(field), Reason: syntaxErr.Error()}
+	}
+	return expr, err
+}
+
+// GetMetadata returns the metadata along with its current revision
+func (m MetadataManager) GetMetadata(ctx context.Context, id uuid.UUID) (*models.Metadata, int, error) {
+	revision, err := m.repository.GetCurrentRevision(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &revision.Metadata, revision.Number, nil
+}
+
+func (m MetadataManager) GetRevisions(ctx context.Context, id uuid.UUID) (models.Revisions, error) {
+	revisions, err := m.repository.GetRevisions(id)
+	if err != nil {
+		return models.Revisions{}, err
+	}
+	return models.Revisions{Revisions: revisions}, nil
+}
+
+func (m MetadataManager) GetRevision(ctx context.Context, id uuid.UUID, number int) (*models.Revision, error) {
+	return m.repository.GetRevision(id, number)
+}
+
+// Close flushes and closes the repository
+func (m MetadataManager) Close() error {
+	return m.repository.Close()
+}
+
+// WithRoles returns a copy of the manager authorizing the writes of principals according to their roles
+func (m MetadataManager) WithRoles(roles RoleProvider) MetadataManager {
+	m.roles = roles
+	return m
 }
 
 func BuildMetadataManager(repository MetadataRepository, validator MetadataValidator, logger *log.Logger) (MetadataManager, error) {
@@ -3,13 +3,52 @@ package app
 import (
 	"github.com/google/uuid"
 	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/query"
 )
 
+// SearchCriteria describes a search against a MetadataRepository
+type SearchCriteria struct {
+	// Metadata holds the fields that must match
+	Metadata *models.Metadata
+	// Expr is an optional boolean expression that must match as well
+	Expr query.Expr
+	// Match holds the match mode of the fields of Metadata keyed by their canonical name;
+	// fields not listed are matched exactly
+	Match map[string]models.FieldMatch
+	// Sort lists the keys results are ordered by, ties being broken by creation time and then id.
+	// Results are ordered by relevance first when no key is given and the search is scored
+	Sort []SortKey
+	// Offset is the number of results to skip and Limit the maximum number of results to return, 0 meaning no limit
+	Offset int
+	Limit  int
+}
+
+// SortKey is a field to order search results by
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
 type MetadataRepository interface {
-	Insert(metadata *models.Metadata) (id uuid.UUID, err error)
-	Update(id uuid.UUID, metadata *models.Metadata) (err error)
+	// Insert stores the metadata, recording author as the author of its first revision
+	Insert(metadata *models.Metadata, author string) (id uuid.UUID, err error)
+	// Update replaces the metadata and returns its new revision number. A non-zero expectedRevision makes the
+	// update conditional: it fails with a PreconditionFailedError unless it matches the current revision
+	Update(id uuid.UUID, metadata *models.Metadata, expectedRevision int, author string) (revision int, err error)
 	Get(id uuid.UUID) (metadata *models.Metadata, err error)
-	Search(metadata *models.Metadata) (results []models.Metadata, err error)
+	// Delete removes the metadata and records a tombstone revision; its revision history is kept. A non-zero
+	// expectedRevision makes the deletion conditional like the one of Update
+	Delete(id uuid.UUID, expectedRevision int, author string) (err error)
+	GetRevisions(id uuid.UUID) (revisions []models.Revision, err error)
+	GetRevision(id uuid.UUID, number int) (revision *models.Revision, err error)
+	GetCurrentRevision(id uuid.UUID) (revision *models.Revision, err error)
+	// Search returns a page of the metadata matching the criteria along with the total number of matches
+	Search(criteria SearchCriteria) (results []models.Metadata, total int, err error)
+	// Facets counts the metadata matching the criteria per value of each of the fields, along with the
+	// total number of matches. Sorting and paging of the criteria are ignored
+	Facets(criteria SearchCriteria, fields []string) (facets map[string][]models.FacetValue, total int, err error)
+	// Close flushes and releases any durable storage; the repository must not be used afterwards
+	Close() error
 }
 
 type MetadataValidator interface {
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/app"
+	"gopkg.in/yaml.v2"
+)
+
+// APIKeyHeader is the header carrying API keys
+const APIKeyHeader = "X-API-Key"
+
+// minAPIKeyLength is the minimum length of an API key, so that keys cannot be guessed
+const minAPIKeyLength = 16
+
+// APIKey is a static key along with the principal it authenticates
+type APIKey struct {
+	Key     string `yaml:"key"`
+	Subject string `yaml:"subject"`
+	Email   string `yaml:"email"`
+}
+
+// APIKeySet is the content of an API keys file
+type APIKeySet struct {
+	Keys []APIKey `yaml:"keys"`
+}
+
+// APIKeyAuthenticator authenticates requests carrying one of a set of static keys in the X-API-Key header
+type APIKeyAuthenticator struct {
+	// principals are keyed by the SHA-256 digest of their key, so that looking a key up does not leak
+	// its prefix through timing
+	principals map[[sha256.Size]byte]app.Principal
+}
+
+// LoadAPIKeyAuthenticator reads the API keys file at path
+func LoadAPIKeyAuthenticator(path string) (*APIKeyAuthenticator, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewAPIKeyAuthenticator(content)
+}
+
+// NewAPIKeyAuthenticator parses the keys of an API keys file and checks that they are well formed
+func NewAPIKeyAuthenticator(content []byte) (*APIKeyAuthenticator, error) {
+	keySet := APIKeySet{}
+	if err := yaml.UnmarshalStrict(content, &keySet); err != nil {
+		return nil, err
+	}
+	authenticator := &APIKeyAuthenticator{principals: make(map[[sha256.Size]byte]app.Principal, len(keySet.Keys))}
+	for i, key := range keySet.Keys {
+		if key.Subject == "" {
+			return nil, fmt.Errorf("key %d has no subject", i+1)
+		}
+		if len(key.Key) < minAPIKeyLength {
+			return nil, fmt.Errorf("key of %s is shorter than %d characters", key.Subject, minAPIKeyLength)
+		}
+		digest := sha256.Sum256([]byte(key.Key))
+		if _, k := authenticator.principals[digest]; k {
+			return nil, fmt.Errorf("key of %s is defined more than once", key.Subject)
+		}
+		authenticator.principals[digest] = app.Principal{Subject: key.Subject, Email: key.Email, Method: MethodAPIKey}
+	}
+	return authenticator, nil
+}
+
+func (a *APIKeyAuthenticator) Authenticate(req *http.Request) (*app.Principal, error) {
+	key := strings.TrimSpace(req.Header.Get(APIKeyHeader))
+	if key == "" {
+		return nil, nil
+	}
+	principal, k := a.principals[sha256.Sum256([]byte(key))]
+	if !k {
+		return nil, app.UnauthorizedError{Reason: "unknown API key"}
+	}
+	return &principal, nil
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+func Test_APIKeyAuthenticator(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator([]byte(`
+keys:
+  - key: 0123456789abcdef0123
+    subject: ci
+  - key: fedcba9876543210fedc
+    subject: erik
+    email: erik.wu@microsoft.com
+`))
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	principal, err := authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	req.Header.Set(APIKeyHeader, "fedcba9876543210fedc")
+	principal, err = authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, &app.Principal{Subject: "erik", Email: "erik.wu@microsoft.com", Method: MethodAPIKey}, principal)
+
+	req.Header.Set(APIKeyHeader, "fedcba9876543210fedd")
+	_, err = authenticator.Authenticate(req)
+	assert.Equal(t, app.UnauthorizedError{Reason: "unknown API key"}, err)
+}
+
+func Test_APIKeyFile(t *testing.T) {
+	for content, message := range map[string]string{
+		"keys:\n  - key: 0123456789abcdef0123\n":                                                            "key 1 has no subject",
+		"keys:\n  - key: short\n    subject: ci\n":                                                          "key of ci is shorter than 16 characters",
+		"keys:\n  - key: 0123456789abcdef0123\n    subject: ci\n    scope: admin\n":                         "yaml: unmarshal errors:\n  line 4: field scope not found in type auth.APIKey",
+		"keys:\n  - {key: 0123456789abcdef0123, subject: a}\n  - {key: 0123456789abcdef0123, subject: b}\n": "key of b is defined more than once",
+	} {
+		_, err := NewAPIKeyAuthenticator([]byte(content))
+		assert.EqualError(t, err, message)
+	}
+}
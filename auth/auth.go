@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"net/http"
+
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+// Authentication methods of the principals
+const (
+	MethodAPIKey = "api-key"
+	MethodBearer = "bearer"
+)
+
+// Authenticator authenticates the credentials of a request
+type Authenticator interface {
+	// Authenticate returns the principal of the request, or nil if the request does not carry any credentials
+	// the authenticator handles. Credentials that do not authenticate fail with an app.UnauthorizedError
+	Authenticate(req *http.Request) (*app.Principal, error)
+}
+
+// Chain tries each of its authenticators in turn, and returns the principal of the first one that handles
+// the credentials of the request
+type Chain []Authenticator
+
+func (c Chain) Authenticate(req *http.Request) (*app.Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(req)
+		if err != nil || principal != nil {
+			return principal, err
+		}
+	}
+	return nil, nil
+}
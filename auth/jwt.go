@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+// minJWTSecretLength is the minimum length of the HMAC secret, as recommended for HS256 by RFC 7518
+const minJWTSecretLength = 32
+
+// clockSkew is tolerated when checking the validity period of tokens
+const clockSkew = 30 * time.Second
+
+// algorithms maps the supported JWS algorithms to their hash. Other algorithms, none included, are rejected
+var algorithms = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// JWTAuthenticator authenticates requests carrying a JSON Web Token signed with a shared HMAC secret in their
+// Authorization: Bearer header. Tokens must carry a sub and an exp claim; the email claim is optional
+type JWTAuthenticator struct {
+	secret []byte
+	// issuer and audience, when set, must match the iss and aud claims of the tokens
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// LoadJWTAuthenticator reads the HMAC secret from the file at path, ignoring a trailing newline
+func LoadJWTAuthenticator(path string, issuer string, audience string) (*JWTAuthenticator, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewJWTAuthenticator(bytes.TrimRight(content, "\r\n"), issuer, audience)
+}
+
+func NewJWTAuthenticator(secret []byte, issuer string, audience string) (*JWTAuthenticator, error) {
+	if len(secret) < minJWTSecretLength {
+		return nil, fmt.Errorf("the JWT secret is shorter than %d bytes", minJWTSecretLength)
+	}
+	return &JWTAuthenticator{secret: secret, issuer: issuer, audience: audience, now: time.Now}, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Sub      string          `json:"sub"`
+	Email    string          `json:"email"`
+	Iss      string          `json:"iss"`
+	Aud      json.RawMessage `json:"aud"`
+	Exp      *float64        `json:"exp"`
+	Nbf      *float64        `json:"nbf"`
+	audience []string
+}
+
+func (a *JWTAuthenticator) Authenticate(req *http.Request) (*app.Principal, error) {
+	header := req.Header.Get("Authorization")
+	if len(header) < len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return nil, nil
+	}
+	claims, err := a.verify(strings.TrimSpace(header[len("Bearer "):]))
+	if err != nil {
+		return nil, app.UnauthorizedError{Reason: err.Error()}
+	}
+	return &app.Principal{Subject: claims.Sub, Email: claims.Email, Method: MethodBearer}, nil
+}
+
+// verify checks the signature and claims of a token in the JWS compact serialization
+func (a *JWTAuthenticator) verify(token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	header := jwtHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errors.New("malformed token header")
+	}
+	newHash, k := algorithms[header.Alg]
+	if !k {
+		return nil, fmt.Errorf("unsupported token algorithm %s", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed token signature")
+	}
+	mac := hmac.New(newHash, a.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("invalid token signature")
+	}
+
+	claims := &jwtClaims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+	if err := claims.decodeAudience(); err != nil {
+		return nil, err
+	}
+	// exp and nbf are numbers of seconds since the epoch
+	now := float64(a.now().UnixNano()) / float64(time.Second)
+	skew := clockSkew.Seconds()
+	if claims.Exp == nil {
+		return nil, errors.New("token has no expiration")
+	}
+	if now-skew > *claims.Exp {
+		return nil, errors.New("token expired")
+	}
+	if claims.Nbf != nil && now+skew < *claims.Nbf {
+		return nil, errors.New("token not valid yet")
+	}
+	if claims.Sub == "" {
+		return nil, errors.New("token has no subject")
+	}
+	if a.issuer != "" && claims.Iss != a.issuer {
+		return nil, fmt.Errorf("token issuer %s is not %s", claims.Iss, a.issuer)
+	}
+	if a.audience != "" && !contains(claims.audience, a.audience) {
+		return nil, fmt.Errorf("token audience does not include %s", a.audience)
+	}
+	return claims, nil
+}
+
+// decodeAudience decodes the aud claim, which is either a string or an array of strings
+func (c *jwtClaims) decodeAudience() error {
+	if len(c.Aud) == 0 {
+		return nil
+	}
+	var audience string
+	if err := json.Unmarshal(c.Aud, &audience); err == nil {
+		c.audience = []string{audience}
+		return nil
+	}
+	if err := json.Unmarshal(c.Aud, &c.audience); err != nil {
+		return errors.New("malformed token audience")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, target)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
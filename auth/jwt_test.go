@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+// sign returns an HS256 token of the claims
+func sign(header string, claims string, key []byte) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func Test_JWTAuthenticator(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(secret, "https://issuer.example.com", "yamlr")
+	assert.NoError(t, err)
+	authenticator.now = func() time.Time { return time.Unix(1600000000, 0) }
+	header := `{"alg":"HS256","typ":"JWT"}`
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	principal, err := authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	req.Header.Set("Authorization", "Bearer "+sign(header,
+		`{"sub":"erik","email":"erik.wu@microsoft.com","iss":"https://issuer.example.com","aud":["yamlr","other"],"exp":1600000100}`, secret))
+	principal, err = authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, &app.Principal{Subject: "erik", Email: "erik.wu@microsoft.com", Method: MethodBearer}, principal)
+
+	for claims, reason := range map[string]string{
+		`{"sub":"erik","iss":"https://issuer.example.com","aud":"yamlr"}`:                                   "token has no expiration",
+		`{"sub":"erik","iss":"https://issuer.example.com","aud":"yamlr","exp":1599999900}`:                  "token expired",
+		`{"sub":"erik","iss":"https://issuer.example.com","aud":"yamlr","exp":1600000100,"nbf":1600000050}`: "token not valid yet",
+		`{"iss":"https://issuer.example.com","aud":"yamlr","exp":1600000100}`:                               "token has no subject",
+		`{"sub":"erik","iss":"https://other.example.com","aud":"yamlr","exp":1600000100}`:                   "token issuer https://other.example.com is not https://issuer.example.com",
+		`{"sub":"erik","iss":"https://issuer.example.com","aud":"other","exp":1600000100}`:                  "token audience does not include yamlr",
+	} {
+		req.Header.Set("Authorization", "Bearer "+sign(header, claims, secret))
+		_, err = authenticator.Authenticate(req)
+		assert.Equal(t, app.UnauthorizedError{Reason: reason}, err, claims)
+	}
+
+	// within the tolerated clock skew
+	req.Header.Set("Authorization", "Bearer "+sign(header, `{"sub":"erik","iss":"https://issuer.example.com","aud":"yamlr","exp":1599999990}`, secret))
+	_, err = authenticator.Authenticate(req)
+	assert.NoError(t, err)
+
+	claims := `{"sub":"erik","iss":"https://issuer.example.com","aud":"yamlr","exp":1600000100}`
+	for token, reason := range map[string]string{
+		sign(header, claims, []byte("fedcba9876543210fedcba9876543210")): "invalid token signature",
+		sign(`{"alg":"none"}`, claims, secret):                           "unsupported token algorithm none",
+		sign(header, claims, secret) + ".extra":                          "malformed token",
+		"not a token":                                                    "malformed token",
+	} {
+		req.Header.Set("Authorization", "Bearer "+token)
+		_, err = authenticator.Authenticate(req)
+		assert.Equal(t, app.UnauthorizedError{Reason: reason}, err, token)
+	}
+}
+
+func Test_JWTSecret(t *testing.T) {
+	_, err := NewJWTAuthenticator([]byte("secret"), "", "")
+	assert.EqualError(t, err, "the JWT secret is shorter than 32 bytes")
+}
+
+func Test_Chain(t *testing.T) {
+	keys, err := NewAPIKeyAuthenticator([]byte("keys:\n  - {key: 0123456789abcdef0123, subject: ci}\n"))
+	assert.NoError(t, err)
+	tokens, err := NewJWTAuthenticator(secret, "", "")
+	assert.NoError(t, err)
+	chain := Chain{keys, tokens}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	req.Header.Set("Authorization", "Bearer "+sign(`{"alg":"HS512"}`, `{"sub":"erik","exp":4000000000}`, secret))
+	_, err = chain.Authenticate(req)
+	assert.Equal(t, app.UnauthorizedError{Reason: "invalid token signature"}, err)
+
+	req.Header.Set("Authorization", "Bearer "+sign(`{"alg":"HS256"}`, `{"sub":"erik","exp":4000000000}`, secret))
+	principal, err := chain.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "erik", principal.Subject)
+
+	req.Header.Set(APIKeyHeader, "0123456789abcdef0123")
+	principal, err = chain.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", principal.Subject)
+}
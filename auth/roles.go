@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/app"
+	"gopkg.in/yaml.v2"
+)
+
+// AnyCompany lists the roles a principal holds for every company
+const AnyCompany = "*"
+
+// CompanyRoles lists the principals holding each role for a company, by email or, for principals without
+// one, by subject
+type CompanyRoles struct {
+	Readers []string `yaml:"readers"`
+	Writers []string `yaml:"writers"`
+	Admins  []string `yaml:"admins"`
+}
+
+// RoleSet is the content of a roles file
+type RoleSet struct {
+	// DefaultRole is the role of the principals not listed for a company, writer if empty
+	DefaultRole string                  `yaml:"defaultRole"`
+	Companies   map[string]CompanyRoles `yaml:"companies"`
+}
+
+// Roles assigns roles to principals per company. A principal listed for both a company and AnyCompany
+// holds the highest of the two roles
+type Roles struct {
+	defaultRole string
+	// roles are keyed by lowercased company, and then by normalized email or subject
+	roles map[string]map[string]string
+}
+
+// LoadRoles reads the roles file at path
+func LoadRoles(path string) (*Roles, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewRoles(content)
+}
+
+// NewRoles parses the roles of a roles file and checks that they are well formed
+func NewRoles(content []byte) (*Roles, error) {
+	roleSet := RoleSet{}
+	if err := yaml.UnmarshalStrict(content, &roleSet); err != nil {
+		return nil, err
+	}
+	roles := &Roles{defaultRole: roleSet.DefaultRole, roles: make(map[string]map[string]string, len(roleSet.Companies))}
+	switch roles.defaultRole {
+	case "":
+		roles.defaultRole = app.RoleWriter
+	case app.RoleReader, app.RoleWriter, app.RoleAdmin:
+	default:
+		return nil, fmt.Errorf("unknown default role %s", roleSet.DefaultRole)
+	}
+	for company, companyRoles := range roleSet.Companies {
+		key := strings.ToLower(strings.TrimSpace(company))
+		if _, k := roles.roles[key]; k {
+			return nil, fmt.Errorf("company %s is defined more than once", key)
+		}
+		principals := make(map[string]string)
+		for role, names := range map[string][]string{app.RoleReader: companyRoles.Readers, app.RoleWriter: companyRoles.Writers, app.RoleAdmin: companyRoles.Admins} {
+			for _, name := range names {
+				name = app.NormalizeEmail(name)
+				if _, k := principals[name]; k {
+					return nil, fmt.Errorf("%s holds more than one role for %s", name, company)
+				}
+				principals[name] = role
+			}
+		}
+		roles.roles[key] = principals
+	}
+	return roles, nil
+}
+
+// Role returns the role of the principal for the company. Principals are looked up by email, and by subject
+// only if they have no email, as subjects of different authenticators may collide
+func (r *Roles) Role(principal app.Principal, company string) string {
+	name := app.NormalizeEmail(principal.Email)
+	if name == "" {
+		name = app.NormalizeEmail(principal.Subject)
+	}
+	role := ""
+	for _, key := range []string{strings.ToLower(strings.TrimSpace(company)), AnyCompany} {
+		if candidate, k := r.roles[key][name]; k && app.RoleRank(candidate) > app.RoleRank(role) {
+			role = candidate
+		}
+	}
+	if role == "" {
+		return r.defaultRole
+	}
+	return role
+}
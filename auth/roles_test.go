@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+func Test_Roles(t *testing.T) {
+	roles, err := NewRoles([]byte(`
+defaultRole: reader
+companies:
+  Microsoft:
+    writers: [erik.wu@microsoft.com, "Jane Doe <Jane.Doe@microsoft.com>"]
+    admins: [ci]
+  "*":
+    writers: [ci]
+    admins: [jane.doe@microsoft.com]
+`))
+	assert.NoError(t, err)
+
+	erik := app.Principal{Subject: "erik", Email: "Erik.Wu@microsoft.com"}
+	assert.Equal(t, app.RoleWriter, roles.Role(erik, "microsoft"))
+	assert.Equal(t, app.RoleReader, roles.Role(erik, "Random Inc."))
+	// the highest of the company and wildcard roles
+	jane := app.Principal{Subject: "jane", Email: "jane.doe@microsoft.com"}
+	assert.Equal(t, app.RoleAdmin, roles.Role(jane, "Microsoft"))
+	assert.Equal(t, app.RoleAdmin, roles.Role(jane, "Random Inc."))
+	// principals without email are matched by subject
+	ci := app.Principal{Subject: "ci"}
+	assert.Equal(t, app.RoleAdmin, roles.Role(ci, "Microsoft"))
+	assert.Equal(t, app.RoleWriter, roles.Role(ci, "Random Inc."))
+	// principals with an email are not matched by subject, e.g. a token whose sub collides with an API key
+	impostor := app.Principal{Subject: "ci", Email: "someone@example.com"}
+	assert.Equal(t, app.RoleReader, roles.Role(impostor, "Microsoft"))
+	assert.Equal(t, app.RoleReader, roles.Role(impostor, "Random Inc."))
+
+	roles, err = NewRoles([]byte("companies:\n  Microsoft:\n    readers: [erik.wu@microsoft.com]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, app.RoleReader, roles.Role(erik, "Microsoft"))
+	assert.Equal(t, app.RoleWriter, roles.Role(erik, "Random Inc."))
+}
+
+func Test_RolesFile(t *testing.T) {
+	for content, message := range map[string]string{
+		"defaultRole: owner\n": "unknown default role owner",
+		"companies:\n  Microsoft:\n    readers: [a@b.com]\n  microsoft:\n    admins: [c@d.com]\n": "company microsoft is defined more than once",
+		"companies:\n  Microsoft:\n    readers: [a@b.com]\n    admins: [A@b.com]\n":               "a@b.com holds more than one role for Microsoft",
+		"companies:\n  Microsoft:\n    owners: [a@b.com]\n":                                       "yaml: unmarshal errors:\n  line 3: field owners not found in type auth.CompanyRoles",
+	} {
+		_, err := NewRoles([]byte(content))
+		assert.EqualError(t, err, message)
+	}
+}
@@ -4,23 +4,106 @@ import (
 	"flag"
 	"fmt"
 	"gitlab.com/erikwu09/yamlr/app"
-	"gitlab.com/erikwu09/yamlr/memoryrepo"
+	"gitlab.com/erikwu09/yamlr/auth"
+	"gitlab.com/erikwu09/yamlr/memoryRepo"
 	"gitlab.com/erikwu09/yamlr/server"
 	"gitlab.com/erikwu09/yamlr/validation"
 	"log"
 	"os"
+	"time"
 )
 
 func main() {
 	logger := log.New(os.Stdout, "", log.LstdFlags|log.Lshortfile)
 	logger.Println("Starting service")
 	var port = flag.Int("port", 8082, "port")
+	var dataDir = flag.String("data-dir", "", "directory for the write-ahead log and snapshots; in-memory only if empty")
+	var compactEvery = flag.Int("compact-every", memoryrepo.DefaultCompactEvery, "number of writes between two snapshots")
+	var rules = flag.String("rules", "", "YAML file of validation rules; the built-in validation is used if empty")
+	var strict = flag.Bool("strict", true, "reject documents with unknown or duplicate keys; -strict=false ignores them")
+	var readTimeout = flag.Duration("read-timeout", 15*time.Second, "maximum duration for reading a request, 0 for none")
+	var writeTimeout = flag.Duration("write-timeout", 30*time.Second, "maximum duration for writing a response, 0 for none")
+	var idleTimeout = flag.Duration("idle-timeout", 60*time.Second, "maximum duration a kept-alive connection waits for the next request, 0 for none")
+	var shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "maximum duration in-flight requests are given to complete on shutdown, 0 for none")
+	var tlsCert = flag.String("tls-cert", "", "PEM certificate file; the service is served over HTTPS when set along with -tls-key")
+	var tlsKey = flag.String("tls-key", "", "PEM private key file of -tls-cert")
+	var tlsClientCA = flag.String("tls-client-ca", "", "PEM bundle of the CAs client certificates must be signed by; client certificates are not required if empty")
+	var tlsReloadInterval = flag.Duration("tls-reload-interval", server.DefaultTLSReloadInterval, "interval at which the TLS files are checked for changes")
+	var apiKeys = flag.String("api-keys", "", "YAML file of the API keys accepted in the X-API-Key header")
+	var jwtSecretFile = flag.String("jwt-secret-file", "", "file holding the HMAC secret bearer tokens are signed with")
+	var jwtIssuer = flag.String("jwt-issuer", "", "issuer bearer tokens must have been issued by, any if empty")
+	var jwtAudience = flag.String("jwt-audience", "", "audience bearer tokens must have been issued for, any if empty")
+	var anonymousReads = flag.Bool("anonymous-reads", false, "let requests without credentials read and search metadata when authentication is enabled")
+	var rolesFile = flag.String("roles", "", "YAML file of the reader, writer and admin roles per company; every principal is a writer if empty")
+	flag.Parse()
 	logger.Println(fmt.Sprintf("listening on port %d", *port))
-	repo, err := memoryrepo.GetMemoryRepository()
+	var repo app.MetadataRepository
+	var err error
+	if *dataDir == "" {
+		repo, err = memoryrepo.GetMemoryRepository()
+	} else {
+		logger.Println(fmt.Sprintf("persisting metadata to %s", *dataDir))
+		repo, err = memoryrepo.GetDurableMemoryRepository(*dataDir, *compactEvery, logger)
+	}
 	if err != nil {
 		logger.Fatal(err)
 	}
-	mgr, _ := app.BuildMetadataManager(repo, validation.SimpleValidator{}, logger)
-	service := server.BuildYamlApp(*port, &mgr, logger)
-	service.Run()
+	var validator app.MetadataValidator = validation.SimpleValidator{}
+	if *rules != "" {
+		logger.Println(fmt.Sprintf("loading validation rules from %s", *rules))
+		validator, err = validation.LoadRuleValidator(*rules)
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}
+	var authenticators auth.Chain
+	if *apiKeys != "" {
+		logger.Println(fmt.Sprintf("loading API keys from %s", *apiKeys))
+		authenticator, err := auth.LoadAPIKeyAuthenticator(*apiKeys)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	if *jwtSecretFile != "" {
+		authenticator, err := auth.LoadJWTAuthenticator(*jwtSecretFile, *jwtIssuer, *jwtAudience)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	var authenticator auth.Authenticator
+	if len(authenticators) > 0 {
+		authenticator = authenticators
+	} else {
+		logger.Println("authentication is disabled, every request is anonymous")
+	}
+	mgr, _ := app.BuildMetadataManager(repo, validator, logger)
+	if *rolesFile != "" {
+		logger.Println(fmt.Sprintf("loading roles from %s", *rolesFile))
+		roles, err := auth.LoadRoles(*rolesFile)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if authenticator == nil {
+			logger.Println("roles are ignored as authentication is disabled")
+		}
+		mgr = mgr.WithRoles(roles)
+	}
+	service := server.BuildYamlApp(*port, &mgr, logger, server.Options{
+		Strict:            *strict,
+		ReadTimeout:       *readTimeout,
+		WriteTimeout:      *writeTimeout,
+		IdleTimeout:       *idleTimeout,
+		ShutdownTimeout:   *shutdownTimeout,
+		TLSCertFile:       *tlsCert,
+		TLSKeyFile:        *tlsKey,
+		TLSClientCAFile:   *tlsClientCA,
+		TLSReloadInterval: *tlsReloadInterval,
+		Authenticator:     authenticator,
+		AnonymousReads:    *anonymousReads,
+	})
+	if err := service.Run(); err != nil {
+		logger.Fatal(err)
+	}
 }
@@ -0,0 +1,65 @@
+package memoryrepo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common english words that carry no meaning for free-text search
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "but": {}, "by": {},
+	"for": {}, "from": {}, "has": {}, "have": {}, "if": {}, "in": {}, "into": {}, "is": {}, "it": {},
+	"its": {}, "no": {}, "not": {}, "of": {}, "on": {}, "or": {}, "such": {}, "that": {}, "the": {},
+	"their": {}, "then": {}, "there": {}, "these": {}, "they": {}, "this": {}, "to": {}, "was": {},
+	"were": {}, "will": {}, "with": {}, "which": {}, "who": {}, "what": {}, "when": {}, "where": {},
+}
+
+// analyze turns free text into search terms: the text is split on anything that is not a letter or
+// a digit, lowercased, stripped of stop words and stemmed
+func analyze(text string) []string {
+	tokens := strings.FieldsFunc(text, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.ToLower(token)
+		if _, k := stopWords[token]; k {
+			continue
+		}
+		terms = append(terms, stem(token))
+	}
+	return terms
+}
+
+// stem strips the most common english inflections so that e.g. "services", "service" and "serviced"
+// end up as the same term. It is intentionally much simpler than a full Porter stemmer
+func stem(term string) string {
+	switch {
+	case len(term) > 4 && strings.HasSuffix(term, "ies"):
+		return term[:len(term)-3] + "y"
+	case strings.HasSuffix(term, "sses"):
+		return term[:len(term)-2]
+	case len(term) > 3 && strings.HasSuffix(term, "s") &&
+		!strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us") && !strings.HasSuffix(term, "is"):
+		term = term[:len(term)-1]
+	}
+	for _, suffix := range []string{"ing", "ed", "ly"} {
+		if len(term) > len(suffix)+2 && strings.HasSuffix(term, suffix) {
+			return undouble(term[:len(term)-len(suffix)])
+		}
+	}
+	// "service" and "servic(ing)" should share a stem
+	if len(term) > 3 && strings.HasSuffix(term, "e") && !strings.HasSuffix(term, "ee") {
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+// undouble removes the doubled consonant left by suffixes like "running" -> "runn"
+func undouble(term string) string {
+	n := len(term)
+	if n > 3 && term[n-1] == term[n-2] && !strings.ContainsRune("aeiouslz", rune(term[n-1])) {
+		return term[:n-1]
+	}
+	return term
+}
@@ -1,19 +1,62 @@
 package memoryrepo
 
 import (
+	"time"
+
 	"gitlab.com/erikwu09/yamlr/models"
 )
 
 //MetadataDAO is an internal access object class for memoryRepository
 type MetadataDAO struct {
 	models.Metadata
-	Id            string
-	MaintainerIDs []string
+	Id string
+	// Revision is the number of the latest revision of the metadata
+	Revision  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// UpdatedBy is the author of the latest revision
+	UpdatedBy string
+	// DescriptionLength is the number of terms of the description, used for relevance scoring
+	DescriptionLength int
+	// VersionKey is the sortable form of the version, empty if the version is not a semantic version
+	VersionKey string
 }
 
 //MaintainerDAO is an internal access object class for memoryRepository
 type MaintainerDAO struct {
 	models.Maintainer
+	Id string
+}
+
+//MaintainerLinkDAO joins a metadata to one of its maintainers
+type MaintainerLinkDAO struct {
+	MetadataId   string
+	MaintainerId string
+}
+
+//RevisionDAO is an internal access object class for the revision history of a metadata
+type RevisionDAO struct {
+	models.Revision
+	MetadataId string
+}
+
+//PostingDAO records how often a term occurs in the description of a metadata
+type PostingDAO struct {
+	Term       string
+	MetadataId string
+	Frequency  int
+}
+
+//TextStatsDAO holds the collection statistics of the description inverted index
+type TextStatsDAO struct {
 	Id          string
-	MetadataIds []string
+	Documents   int
+	TotalLength int
+}
+
+//TrigramDAO records that a trigram occurs in a field of a metadata
+type TrigramDAO struct {
+	Field      string
+	Trigram    string
+	MetadataId string
 }
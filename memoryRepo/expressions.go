@@ -0,0 +1,96 @@
+package memoryrepo
+
+import (
+	"errors"
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/query"
+	"gitlab.com/erikwu09/yamlr/utils"
+)
+
+// queryByExpr evaluates a boolean search expression against the indexes. Relevance scores of
+// description terms are accumulated into scores
+func (r *memoryRepository) queryByExpr(expr query.Expr, tx *memdb.Txn, scores *map[*MetadataDAO]float64) (*utils.Set, error) {
+	switch e := expr.(type) {
+	case query.And:
+		left, err := r.queryByExpr(e.Left, tx, scores)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.queryByExpr(e.Right, tx, scores)
+		if err != nil {
+			return nil, err
+		}
+		return left.Intersect(*right), nil
+	case query.Or:
+		left, err := r.queryByExpr(e.Left, tx, scores)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.queryByExpr(e.Right, tx, scores)
+		if err != nil {
+			return nil, err
+		}
+		return left.Union(*right), nil
+	case query.Not:
+		// a negated description does not contribute to relevance
+		inner, err := r.queryByExpr(e.Expr, tx, new(map[*MetadataDAO]float64))
+		if err != nil {
+			return nil, err
+		}
+		all, err := r.queryAll(tx)
+		if err != nil {
+			return nil, err
+		}
+		return all.Difference(*inner), nil
+	case query.Term:
+		switch e.Field {
+		case "Email":
+			return r.queryByEmails([]string{e.Value}, tx)
+		case "Version":
+			return r.queryByVersion(e.Value, tx)
+		case "Description":
+			result, descriptionScores, err := r.queryByDescription(e.Value, tx)
+			if err != nil {
+				return nil, err
+			}
+			*scores = mergeScores(*scores, descriptionScores)
+			return result, nil
+		default:
+			return r.query(e.Field, e.Value, tx)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported expression %v", expr)
+	}
+}
+
+// queryAll returns every stored metadata
+func (r *memoryRepository) queryAll(tx *memdb.Txn) (*utils.Set, error) {
+	result := utils.NewSet()
+	it, err := tx.Get(metadataTable, "id")
+	if err != nil {
+		return nil, err
+	}
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		metadata, k := obj.(*MetadataDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		result.Add(metadata)
+	}
+	return result, nil
+}
+
+func mergeScores(scores map[*MetadataDAO]float64, other map[*MetadataDAO]float64) map[*MetadataDAO]float64 {
+	if other == nil {
+		return scores
+	}
+	if scores == nil {
+		scores = make(map[*MetadataDAO]float64, len(other))
+	}
+	for metadata, score := range other {
+		scores[metadata] += score
+	}
+	return scores
+}
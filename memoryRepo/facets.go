@@ -0,0 +1,142 @@
+package memoryrepo
+
+import (
+	"errors"
+	"sort"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+// Facets counts the metadata matching the criteria per value of each field by walking the index of the field.
+// Email counts metadata per maintainer email
+func (r *memoryRepository) Facets(criteria app.SearchCriteria, fields []string) (facets map[string][]models.FacetValue, total int, err error) {
+	tx := r.memoryDb.Txn(false)
+	defer tx.Abort()
+
+	// ids holds the ids of the matching metadata, nil if the whole catalog is aggregated
+	var ids map[string]bool
+	params := getSearchParams(criteria)
+	if isFiltered(params) {
+		results, _, err := r.queryByParamsTx(params, tx)
+		if err != nil {
+			return nil, 0, err
+		}
+		ids = getIds(results)
+		total = len(ids)
+	} else if total, err = r.count(tx); err != nil {
+		return nil, 0, err
+	}
+
+	facets = make(map[string][]models.FacetValue, len(fields))
+	for _, field := range fields {
+		var counts map[string]int
+		if field == "Email" {
+			counts, err = r.countByEmail(ids, tx)
+		} else {
+			counts, err = r.countByIndex(field, ids, tx)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		facets[field] = sortFacetValues(counts)
+	}
+	return
+}
+
+// isFiltered returns whether any of the query parameters restricts the results
+func isFiltered(params map[string]interface{}) bool {
+	for _, queryVal := range params {
+		if !isQueryValEmptyOrNull(queryVal) {
+			return true
+		}
+	}
+	return false
+}
+
+func getIds(results []*MetadataDAO) map[string]bool {
+	ids := make(map[string]bool, len(results))
+	for _, result := range results {
+		ids[result.Id] = true
+	}
+	return ids
+}
+
+func (r *memoryRepository) count(tx *memdb.Txn) (int, error) {
+	it, err := tx.Get(metadataTable, "id")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		count++
+	}
+	return count, nil
+}
+
+// countByIndex counts the metadata per value of an indexed field of the metadata table.
+// Only the metadata in ids are counted unless ids is nil
+func (r *memoryRepository) countByIndex(index string, ids map[string]bool, tx *memdb.Txn) (map[string]int, error) {
+	it, err := tx.Get(metadataTable, index)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		metadataDAO, k := obj.(*MetadataDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		if ids != nil && !ids[metadataDAO.Id] {
+			continue
+		}
+		value := fieldValue(&metadataDAO.Metadata, index)
+		if index == "License" {
+			value = canonicalLicense(value)
+		}
+		counts[value]++
+	}
+	return counts, nil
+}
+
+// countByEmail counts the metadata linked to each maintainer.
+// Only the metadata in ids are counted unless ids is nil
+func (r *memoryRepository) countByEmail(ids map[string]bool, tx *memdb.Txn) (map[string]int, error) {
+	it, err := tx.Get(maintainerTable, "Email")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		maintainer, k := obj.(*MaintainerDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		links, err := r.getMaintainerLinks("MaintainerId", maintainer.Id, tx)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			if ids == nil || ids[link.MetadataId] {
+				counts[maintainer.Email]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// sortFacetValues orders the values by descending count and then by value
+func sortFacetValues(counts map[string]int) []models.FacetValue {
+	values := make([]models.FacetValue, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, models.FacetValue{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	return values
+}
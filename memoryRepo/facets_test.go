@@ -0,0 +1,68 @@
+package memoryrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/query"
+)
+
+func Test_Facets(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	aks := dummyMetadata("", "", "microsoft.com")
+	aks.Company = "Microsoft"
+	aks.License = "MIT"
+	vscode := dummyMetadata("", "", "microsoft.com")
+	vscode.Company = "Microsoft"
+	vscode.License = "MIT"
+	vscode.Maintainers = append(vscode.Maintainers, &models.Maintainer{Name: "Jane", Email: "jane@microsoft.com"})
+	ecs := dummyMetadata("", "", "amazon.com")
+	ecs.Company = "Amazon"
+	ecs.License = "Apache-2.0"
+	for _, m := range []*models.Metadata{aks, vscode, ecs} {
+		repo.Insert(m, "")
+	}
+
+	facets, total, err := repo.Facets(app.SearchCriteria{Metadata: &models.Metadata{}}, []string{"Company", "License", "Email"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []models.FacetValue{{Value: "Microsoft", Count: 2}, {Value: "Amazon", Count: 1}}, facets["Company"])
+	assert.Equal(t, []models.FacetValue{{Value: "MIT", Count: 2}, {Value: "Apache-2.0", Count: 1}}, facets["License"])
+	assert.Equal(t, []models.FacetValue{{Value: "email@microsoft.com", Count: 2}, {Value: "email@amazon.com", Count: 1},
+		{Value: "jane@microsoft.com", Count: 1}}, facets["Email"])
+
+	expr, err := query.Parse("license:MIT")
+	assert.NoError(t, err)
+	facets, total, err = repo.Facets(app.SearchCriteria{Metadata: &models.Metadata{}, Expr: expr}, []string{"Company", "Email"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []models.FacetValue{{Value: "Microsoft", Count: 2}}, facets["Company"])
+	assert.Equal(t, []models.FacetValue{{Value: "email@microsoft.com", Count: 2}, {Value: "jane@microsoft.com", Count: 1}}, facets["Email"])
+
+	facets, total, err = repo.Facets(app.SearchCriteria{Metadata: &models.Metadata{Company: "Google"}}, []string{"Title"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, facets["Title"])
+}
+
+func Test_EmailFacetsAreSearchable(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	// emails were journaled as <address> before they were stored bare
+	legacy := dummyMetadata("", "", "")
+	legacy.Maintainers = []*models.Maintainer{&models.Maintainer{Name: "Legacy", Email: "<Legacy@Example.com>"}}
+	repo.Insert(legacy, "")
+	repo.Insert(dummyMetadata("", "", "example.com"), "")
+
+	facets, _, err := repo.Facets(app.SearchCriteria{Metadata: &models.Metadata{}}, []string{"Email"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FacetValue{{Value: "email@example.com", Count: 1}, {Value: "legacy@example.com", Count: 1}}, facets["Email"])
+	for _, value := range facets["Email"] {
+		expr, err := query.Parse("email:" + value.Value)
+		assert.NoError(t, err)
+		results, _, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{}, Expr: expr})
+		assert.NoError(t, err)
+		assert.True(t, len(results) == value.Count, value.Value)
+	}
+}
@@ -0,0 +1,137 @@
+package memoryrepo
+
+import (
+	"errors"
+	"math"
+
+	memdb "github.com/hashicorp/go-memdb"
+)
+
+const postingTable string = "description_postings"
+const textStatsTable string = "description_stats"
+
+// the id of the single row of textStatsTable
+const textStatsID string = "description"
+
+// BM25 tuning parameters
+const bm25K1 float64 = 1.2
+const bm25B float64 = 0.75
+
+// indexDescription replaces the postings of a metadata in the description inverted index.
+// previous is the metadata being replaced, or nil for a new metadata
+func (r *memoryRepository) indexDescription(metadataDAO *MetadataDAO, previous *MetadataDAO, tx *memdb.Txn) error {
+	if previous != nil {
+		if err := r.unindexDescription(previous, tx); err != nil {
+			return err
+		}
+	}
+	terms := analyze(metadataDAO.Description)
+	frequencies := make(map[string]int)
+	for _, term := range terms {
+		frequencies[term]++
+	}
+	for term, frequency := range frequencies {
+		posting := &PostingDAO{Term: term, MetadataId: metadataDAO.Id, Frequency: frequency}
+		if err := tx.Insert(postingTable, posting); err != nil {
+			return err
+		}
+	}
+	metadataDAO.DescriptionLength = len(terms)
+	return r.updateTextStats(1, len(terms), tx)
+}
+
+// unindexDescription removes a metadata from the description inverted index
+func (r *memoryRepository) unindexDescription(metadataDAO *MetadataDAO, tx *memdb.Txn) error {
+	if _, err := tx.DeleteAll(postingTable, "MetadataId", metadataDAO.Id); err != nil {
+		return err
+	}
+	return r.updateTextStats(-1, -metadataDAO.DescriptionLength, tx)
+}
+
+func (r *memoryRepository) updateTextStats(documents int, length int, tx *memdb.Txn) error {
+	stats, err := r.getTextStats(tx)
+	if err != nil {
+		return err
+	}
+	updated := *stats
+	updated.Documents += documents
+	updated.TotalLength += length
+	return tx.Insert(textStatsTable, &updated)
+}
+
+func (r *memoryRepository) getTextStats(tx *memdb.Txn) (*TextStatsDAO, error) {
+	res, err := tx.First(textStatsTable, "id", textStatsID)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return &TextStatsDAO{Id: textStatsID}, nil
+	}
+	stats, k := res.(*TextStatsDAO)
+	if !k {
+		return nil, errors.New("something went wrong")
+	}
+	return stats, nil
+}
+
+// searchDescription returns the ids of every metadata whose description contains at least one of the
+// terms of the query, along with its BM25 relevance score
+func (r *memoryRepository) searchDescription(query string, tx *memdb.Txn) (map[string]float64, error) {
+	scores := make(map[string]float64)
+	stats, err := r.getTextStats(tx)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Documents == 0 {
+		return scores, nil
+	}
+	averageLength := float64(stats.TotalLength) / float64(stats.Documents)
+	if averageLength == 0 {
+		averageLength = 1
+	}
+
+	seen := make(map[string]struct{})
+	for _, term := range analyze(query) {
+		if _, k := seen[term]; k {
+			continue
+		}
+		seen[term] = struct{}{}
+
+		postings, err := r.getPostings(term, tx)
+		if err != nil {
+			return nil, err
+		}
+		n := float64(len(postings))
+		idf := math.Log(1 + (float64(stats.Documents)-n+0.5)/(n+0.5))
+		for _, posting := range postings {
+			res, err := tx.First(metadataTable, "id", posting.MetadataId)
+			if err != nil {
+				return nil, err
+			}
+			metadataDAO, k := res.(*MetadataDAO)
+			if !k {
+				return nil, errors.New("something went wrong")
+			}
+			tf := float64(posting.Frequency)
+			norm := 1 - bm25B + bm25B*float64(metadataDAO.DescriptionLength)/averageLength
+			scores[posting.MetadataId] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+	return scores, nil
+}
+
+func (r *memoryRepository) getPostings(term string, tx *memdb.Txn) ([]*PostingDAO, error) {
+	it, err := tx.Get(postingTable, "Term", term)
+	if err != nil {
+		return nil, err
+	}
+	postings := make([]*PostingDAO, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		posting, k := obj.(*PostingDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		postings = append(postings, posting)
+	}
+	return postings, nil
+}
@@ -0,0 +1,54 @@
+package memoryrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+func Test_Analyze(t *testing.T) {
+	assert.Equal(t, []string{"manag", "apach", "hadoop", "servic"}, analyze("Managed Apache Hadoop Service"))
+	assert.Equal(t, []string{"servic", "servic", "servic"}, analyze("services, serviced and servicing"))
+	assert.Equal(t, []string{"run", "query"}, analyze("The running of queries"))
+	assert.Equal(t, []string{}, analyze("the and of"))
+}
+
+func Test_SearchDescription(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	hadoop := dummyMetadata("hadoop", "", "")
+	hadoop.Description = "Managed Apache Hadoop Service"
+	kubernetes := dummyMetadata("kubernetes", "", "")
+	kubernetes.Description = "Azure Kubernetes Service for managing kubernetes clusters"
+	dynamo := dummyMetadata("dynamo", "", "")
+	dynamo.Description = "Dynamo Database"
+	repo.Insert(hadoop, "")
+	id, _ := repo.Insert(kubernetes, "")
+	repo.Insert(dynamo, "")
+
+	results, _, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Description: "kubernetes services"}})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 2)
+	assert.Equal(t, kubernetes.Title, results[0].Title)
+	assert.Equal(t, hadoop.Title, results[1].Title)
+
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Description: "managed", Title: hadoop.Title}})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 1)
+
+	// the index follows updates and deletes
+	kubernetes.Description = "Container orchestration"
+	_, err = repo.Update(id, kubernetes, 0, "")
+	assert.NoError(t, err)
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Description: "kubernetes"}})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 0)
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Description: "containers orchestration"}})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 1)
+	assert.NoError(t, repo.Delete(id, 0, ""))
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Description: "container"}})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 0)
+}
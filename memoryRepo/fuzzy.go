@@ -0,0 +1,196 @@
+package memoryrepo
+
+import (
+	"errors"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/utils"
+)
+
+const trigramTable string = "trigrams"
+
+// indexTrigrams replaces the trigrams of the fuzzy searchable fields of a metadata
+func (r *memoryRepository) indexTrigrams(metadataDAO *MetadataDAO, tx *memdb.Txn) error {
+	if _, err := tx.DeleteAll(trigramTable, "MetadataId", metadataDAO.Id); err != nil {
+		return err
+	}
+	for field, values := range fuzzyValues(&metadataDAO.Metadata) {
+		seen := make(map[string]struct{})
+		for _, value := range values {
+			for _, trigram := range trigrams(value) {
+				if _, k := seen[trigram]; k {
+					continue
+				}
+				seen[trigram] = struct{}{}
+				if err := tx.Insert(trigramTable, &TrigramDAO{Field: field, Trigram: trigram, MetadataId: metadataDAO.Id}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fuzzyValues returns the values of every fuzzy searchable field of a metadata
+func fuzzyValues(metadata *models.Metadata) map[string][]string {
+	names := make([]string, 0, len(metadata.Maintainers))
+	for _, m := range metadata.Maintainers {
+		if m != nil {
+			names = append(names, m.Name)
+		}
+	}
+	return map[string][]string{
+		"Title":   {metadata.Title},
+		"Company": {metadata.Company},
+		"Name":    names,
+	}
+}
+
+// queryByFuzzy returns the metadata whose field is within q.match.MaxDistance edits of the value,
+// scored by similarity. Candidates are the metadata sharing enough trigrams with the value to possibly
+// be within that distance
+func (r *memoryRepository) queryByFuzzy(field string, q fieldQuery, tx *memdb.Txn) (*utils.Set, map[*MetadataDAO]float64, error) {
+	value := []rune(strings.ToLower(q.value))
+	queryTrigrams := trigrams(q.value)
+	// an insertion, deletion or substitution destroys at most 3 trigrams, a transposition at most 4
+	minShared := len(queryTrigrams) - 4*q.match.MaxDistance
+
+	var candidates []*MetadataDAO
+	if minShared <= 0 {
+		all, err := r.queryAllDAOs(tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		candidates = all
+	} else {
+		shared := make(map[string]int)
+		for _, trigram := range queryTrigrams {
+			it, err := tx.Get(trigramTable, "Trigram", field, trigram)
+			if err != nil {
+				return nil, nil, err
+			}
+			for obj := it.Next(); obj != nil; obj = it.Next() {
+				trigramDAO, k := obj.(*TrigramDAO)
+				if !k {
+					return nil, nil, errors.New("something went wrong")
+				}
+				shared[trigramDAO.MetadataId]++
+			}
+		}
+		for id, count := range shared {
+			if count < minShared {
+				continue
+			}
+			res, err := tx.First(metadataTable, "id", id)
+			if err != nil {
+				return nil, nil, err
+			}
+			metadataDAO, k := res.(*MetadataDAO)
+			if !k {
+				return nil, nil, errors.New("something went wrong")
+			}
+			candidates = append(candidates, metadataDAO)
+		}
+	}
+
+	result := utils.NewSet()
+	scores := make(map[*MetadataDAO]float64)
+	for _, candidate := range candidates {
+		best := -1.0
+		for _, candidateValue := range fuzzyValues(&candidate.Metadata)[field] {
+			other := []rune(strings.ToLower(candidateValue))
+			distance := editDistance(value, other)
+			if distance > q.match.MaxDistance {
+				continue
+			}
+			if similarity := similarity(distance, value, other); similarity > best {
+				best = similarity
+			}
+		}
+		if best >= 0 {
+			result.Add(candidate)
+			scores[candidate] = best
+		}
+	}
+	return result, scores, nil
+}
+
+func (r *memoryRepository) queryAllDAOs(tx *memdb.Txn) ([]*MetadataDAO, error) {
+	it, err := tx.Get(metadataTable, "id")
+	if err != nil {
+		return nil, err
+	}
+	all := make([]*MetadataDAO, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		metadataDAO, k := obj.(*MetadataDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		all = append(all, metadataDAO)
+	}
+	return all, nil
+}
+
+// trigrams returns the distinct trigrams of the lowercased value, padded so that the start and the
+// end of the value form trigrams of their own
+func trigrams(value string) []string {
+	padded := []rune("  " + strings.ToLower(value) + " ")
+	seen := make(map[string]struct{})
+	result := make([]string, 0, len(padded))
+	for i := 0; i+3 <= len(padded); i++ {
+		trigram := string(padded[i : i+3])
+		if _, k := seen[trigram]; k {
+			continue
+		}
+		seen[trigram] = struct{}{}
+		result = append(result, trigram)
+	}
+	return result
+}
+
+// editDistance is the optimal string alignment distance: the number of insertions, deletions,
+// substitutions and transpositions of adjacent characters needed to turn a into b
+func editDistance(a []rune, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= len(b); j++ {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, minInt(d[i][j-1]+1, d[i-1][j-1]+cost))
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// similarity normalizes an edit distance to a score between 0 (nothing in common) and 1 (equal)
+func similarity(distance int, a []rune, b []rune) float64 {
+	length := len(a)
+	if len(b) > length {
+		length = len(b)
+	}
+	if length == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(length)
+}
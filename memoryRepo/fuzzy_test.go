@@ -0,0 +1,64 @@
+package memoryrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+func Test_EditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance([]rune("service"), []rune("service")))
+	assert.Equal(t, 1, editDistance([]rune("servise"), []rune("service")))
+	assert.Equal(t, 1, editDistance([]rune("azur"), []rune("azure")))
+	assert.Equal(t, 1, editDistance([]rune("cortnaa"), []rune("cortana")))
+	assert.Equal(t, 3, editDistance([]rune(""), []rune("ecs")))
+}
+
+func Test_QueryByFuzzy(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	aks := dummyMetadata("", "", "")
+	aks.Title = "Azure Kubernetes Service"
+	aks.Company = "Microsoft"
+	aks.Maintainers = []*models.Maintainer{{Name: "Jane Smith", Email: "jane@microsoft.com"}}
+	eks := dummyMetadata("", "", "")
+	eks.Title = "Amazon Kubernetes Service"
+	eks.Company = "Amazon"
+	eks.Maintainers = []*models.Maintainer{{Name: "John Smith", Email: "john@amazon.com"}}
+	cortana := dummyMetadata("", "", "")
+	cortana.Title = "Cortana"
+	cortana.Company = "Microsoft"
+	for _, m := range []*models.Metadata{aks, eks, cortana} {
+		repo.Insert(m, "")
+	}
+
+	search := func(metadata *models.Metadata, match map[string]models.FieldMatch) []models.Metadata {
+		results, _, err := repo.Search(app.SearchCriteria{Metadata: metadata, Match: match})
+		assert.NoError(t, err)
+		return results
+	}
+	fuzzy := func(distance int) models.FieldMatch {
+		return models.FieldMatch{Mode: models.MatchFuzzy, MaxDistance: distance}
+	}
+
+	results := search(&models.Metadata{Title: "Azur Kubernetes Servise"}, map[string]models.FieldMatch{"Title": fuzzy(2)})
+	assert.True(t, len(results) == 1)
+	assert.Equal(t, aks.Title, results[0].Title)
+
+	// the closest match comes first
+	results = search(&models.Metadata{Title: "Amazn Kubernetes Service"}, map[string]models.FieldMatch{"Title": fuzzy(5)})
+	assert.True(t, len(results) == 2)
+	assert.Equal(t, eks.Title, results[0].Title)
+	assert.Equal(t, aks.Title, results[1].Title)
+
+	results = search(&models.Metadata{Company: "Micosoft"}, map[string]models.FieldMatch{"Company": fuzzy(1)})
+	assert.True(t, len(results) == 2)
+	results = search(&models.Metadata{Company: "Mcrosft"}, map[string]models.FieldMatch{"Company": fuzzy(1)})
+	assert.True(t, len(results) == 0)
+
+	results = search(&models.Metadata{Maintainers: []*models.Maintainer{{Name: "jane smiht"}}},
+		map[string]models.FieldMatch{"Name": fuzzy(1)})
+	assert.True(t, len(results) == 1)
+	assert.Equal(t, aks.Title, results[0].Title)
+}
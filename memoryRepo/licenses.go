@@ -0,0 +1,44 @@
+package memoryrepo
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/erikwu09/yamlr/spdx"
+)
+
+// canonicalLicense returns the canonical SPDX form of a license, or the license itself if it is not a
+// SPDX license expression
+func canonicalLicense(license string) string {
+	canonical, err := spdx.Canonicalize(license)
+	if err != nil {
+		return license
+	}
+	return canonical
+}
+
+// licenseIndex indexes metadata by the canonical SPDX form of their license, so that searching for any
+// spelling of a license (e.g. Apache-2.0, apache 2 or ASL2) finds metadata stored with any other
+type licenseIndex struct{}
+
+func (licenseIndex) FromObject(obj interface{}) (bool, []byte, error) {
+	metadataDAO, k := obj.(*MetadataDAO)
+	if !k {
+		return false, nil, errors.New("something went wrong")
+	}
+	if metadataDAO.License == "" {
+		return false, nil, nil
+	}
+	return true, []byte(canonicalLicense(metadataDAO.License) + "\x00"), nil
+}
+
+func (licenseIndex) FromArgs(args ...interface{}) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("must provide only a single argument")
+	}
+	license, k := args[0].(string)
+	if !k {
+		return nil, fmt.Errorf("argument must be a string: %#v", args[0])
+	}
+	return []byte(canonicalLicense(license) + "\x00"), nil
+}
@@ -0,0 +1,34 @@
+package memoryrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/query"
+)
+
+func Test_LicenseIndexMatchesCanonicalIds(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	// metadata written before licenses were canonicalized on write
+	for _, license := range []string{"Apache-2.0", "apache 2", "ASL2", "MIT"} {
+		m := dummyMetadata("", "", "")
+		m.License = license
+		repo.Insert(m, "")
+	}
+
+	results, _, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{License: "Apache-2.0"}})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	expr, err := query.Parse(`license:"apache license 2.0"`)
+	assert.NoError(t, err)
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{}, Expr: expr})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	facets, _, err := repo.Facets(app.SearchCriteria{Metadata: &models.Metadata{}}, []string{"License"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FacetValue{{Value: "Apache-2.0", Count: 3}, {Value: "MIT", Count: 1}}, facets["License"])
+}
@@ -0,0 +1,125 @@
+package memoryrepo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+// linkMaintainers makes the maintainer links of a metadata match the given maintainers: missing
+// maintainers and links are added, links to maintainers no longer listed are removed and maintainers
+// left without any metadata are dropped. Maintainers are identified by email, so maintainers without
+// one, which rule sets may allow, are not linked
+func (r *memoryRepository) linkMaintainers(metadataID string, maintainers []*models.Maintainer, tx *memdb.Txn) error {
+	wanted := make(map[string]struct{})
+	for _, m := range maintainers {
+		if m == nil || m.Email == "" {
+			continue
+		}
+		maintainer, err := r.getOrAddMaintainer(m, tx)
+		if err != nil {
+			return err
+		}
+		wanted[maintainer.Id] = struct{}{}
+	}
+
+	existing, err := r.getMaintainerLinks("MetadataId", metadataID, tx)
+	if err != nil {
+		return err
+	}
+	for _, link := range existing {
+		if _, k := wanted[link.MaintainerId]; k {
+			delete(wanted, link.MaintainerId)
+			continue
+		}
+		if err := tx.Delete(maintainerLinkTable, link); err != nil {
+			return err
+		}
+		if err := r.removeMaintainerIfOrphaned(link.MaintainerId, tx); err != nil {
+			return err
+		}
+	}
+	for maintainerID := range wanted {
+		link := &MaintainerLinkDAO{MetadataId: metadataID, MaintainerId: maintainerID}
+		if err := tx.Insert(maintainerLinkTable, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMaintainers rejects documents listing the same maintainer more than once. Emails identify
+// maintainers by their normalized address, so the duplicates would otherwise be silently merged
+func checkMaintainers(maintainers []*models.Maintainer) error {
+	emails := make(map[string]struct{})
+	for _, m := range maintainers {
+		if m == nil || m.Email == "" {
+			continue
+		}
+		email := app.NormalizeEmail(m.Email)
+		if _, k := emails[email]; k {
+			return app.ConflictError{Reason: fmt.Sprintf("maintainer email %s is listed more than once", m.Email)}
+		}
+		emails[email] = struct{}{}
+	}
+	return nil
+}
+
+// getMaintainerLinks returns every link matching the value of either the MetadataId or MaintainerId index
+func (r *memoryRepository) getMaintainerLinks(index string, id string, tx *memdb.Txn) ([]*MaintainerLinkDAO, error) {
+	it, err := tx.Get(maintainerLinkTable, index, id)
+	if err != nil {
+		return nil, err
+	}
+	// links are collected before being returned as callers may modify the table
+	links := make([]*MaintainerLinkDAO, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		link, k := obj.(*MaintainerLinkDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// getOrAddMaintainer returns the maintainer of the normalized address of an email. Maintainers are keyed by
+// normalized address so that metadata journaled before emails were stored bare, e.g. as <a@b.com>, still match
+func (r *memoryRepository) getOrAddMaintainer(maintainer *models.Maintainer, tx *memdb.Txn) (*MaintainerDAO, error) {
+	res, err := tx.First(maintainerTable, "Email", app.NormalizeEmail(maintainer.Email))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return r.addNewMaintainer(maintainer, tx)
+	}
+	maintainerDAO, k := res.(*MaintainerDAO)
+	if !k {
+		return nil, errors.New("something went wrong")
+	}
+	return maintainerDAO, nil
+}
+
+func (r *memoryRepository) addNewMaintainer(maintainer *models.Maintainer, tx *memdb.Txn) (*MaintainerDAO, error) {
+	maintainerID := uuid.New()
+	maintainerDAO := &MaintainerDAO{Maintainer: *maintainer, Id: maintainerID.String()}
+	maintainerDAO.Email = app.NormalizeEmail(maintainer.Email)
+	err := tx.Insert(maintainerTable, maintainerDAO)
+	if err != nil {
+		return nil, err
+	}
+	return maintainerDAO, nil
+}
+
+func (r *memoryRepository) removeMaintainerIfOrphaned(maintainerID string, tx *memdb.Txn) error {
+	link, err := tx.First(maintainerLinkTable, "MaintainerId", maintainerID)
+	if err != nil || link != nil {
+		return err
+	}
+	_, err = tx.DeleteAll(maintainerTable, "id", maintainerID)
+	return err
+}
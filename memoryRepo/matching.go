@@ -0,0 +1,128 @@
+package memoryrepo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/utils"
+)
+
+// fieldQuery is a search value along with how it should be matched
+type fieldQuery struct {
+	value string
+	match models.FieldMatch
+}
+
+// lowercaseIndexes maps fields to an index on their lowercased value
+var lowercaseIndexes = map[string]string{
+	"Title":   "TitleLower",
+	"Company": "CompanyLower",
+	"License": "LicenseLower",
+	// urls are only indexed lowercase
+	"Website": "Website",
+	"Source":  "Source",
+}
+
+// queryByMatch looks candidates up in the lowercase index of the field, by value or by prefix, and
+// filters them with the precise match
+func (r *memoryRepository) queryByMatch(field string, q fieldQuery, tx *memdb.Txn) (*utils.Set, error) {
+	index, k := lowercaseIndexes[field]
+	if !k {
+		return nil, fmt.Errorf("field %s does not support match modes", field)
+	}
+	var it memdb.ResultIterator
+	var err error
+	switch q.match.Mode {
+	case models.MatchPrefix:
+		it, err = tx.Get(metadataTable, index+"_prefix", q.value)
+	case models.MatchGlob:
+		it, err = tx.Get(metadataTable, index+"_prefix", globPrefix(q.value))
+	default:
+		it, err = tx.Get(metadataTable, index, q.value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := utils.NewSet()
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		metadata, k := obj.(*MetadataDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		if matches(fieldValue(&metadata.Metadata, field), q) {
+			result.Add(metadata)
+		}
+	}
+	return result, nil
+}
+
+func matches(value string, q fieldQuery) bool {
+	pattern := q.value
+	if q.match.IgnoreCase {
+		value = strings.ToLower(value)
+		pattern = strings.ToLower(pattern)
+	}
+	switch q.match.Mode {
+	case models.MatchPrefix:
+		return strings.HasPrefix(value, pattern)
+	case models.MatchGlob:
+		return globMatch([]rune(pattern), []rune(value))
+	default:
+		return value == pattern
+	}
+}
+
+func fieldValue(metadata *models.Metadata, field string) string {
+	switch field {
+	case "Title":
+		return metadata.Title
+	case "Company":
+		return metadata.Company
+	case "Website":
+		return metadata.Website
+	case "Source":
+		return metadata.Source
+	case "License":
+		return metadata.License
+	default:
+		return ""
+	}
+}
+
+// globPrefix returns the literal part of a glob pattern before its first wildcard
+func globPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// globMatch matches a value against a pattern where * matches any run of characters (including none)
+// and ? matches exactly one character
+func globMatch(pattern []rune, value []rune) bool {
+	// position to resume from when the last * has to swallow one more character
+	star, resume := -1, 0
+	p, v := 0, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star, resume = p, v
+			p++
+		case star >= 0:
+			resume++
+			p, v = star+1, resume
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,56 @@
+package memoryrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+func Test_GlobMatch(t *testing.T) {
+	assert.True(t, globMatch([]rune("Azure*"), []rune("Azure Kubernetes Service")))
+	assert.True(t, globMatch([]rune("*Kubernetes*"), []rune("Azure Kubernetes Service")))
+	assert.True(t, globMatch([]rune("Az?re*Service"), []rune("Azure Kubernetes Service")))
+	assert.True(t, globMatch([]rune("https://*/aks"), []rune("https://microsoft.com/aks")))
+	assert.False(t, globMatch([]rune("Azure"), []rune("Azure Kubernetes Service")))
+	assert.False(t, globMatch([]rune("*Kubernetes"), []rune("Azure Kubernetes Service")))
+	assert.False(t, globMatch([]rune("?"), []rune("")))
+	assert.Equal(t, "Azure ", globPrefix("Azure *"))
+}
+
+func Test_QueryByMatchModes(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	aks := dummyMetadata("", "", "")
+	aks.Title = "Azure Kubernetes Service"
+	aks.Company = "Microsoft"
+	cortana := dummyMetadata("", "", "")
+	cortana.Title = "Cortana"
+	cortana.Company = "Microsoft"
+	ecs := dummyMetadata("", "", "")
+	ecs.Title = "Elastic Container Service"
+	ecs.Company = "Amazon"
+	for _, m := range []*models.Metadata{aks, cortana, ecs} {
+		repo.Insert(m, "")
+	}
+
+	search := func(metadata *models.Metadata, match map[string]models.FieldMatch) []models.Metadata {
+		results, _, err := repo.Search(app.SearchCriteria{Metadata: metadata, Match: match})
+		assert.NoError(t, err)
+		return results
+	}
+
+	assert.True(t, len(search(&models.Metadata{Company: "microsoft"}, nil)) == 0)
+	assert.True(t, len(search(&models.Metadata{Company: "microsoft"},
+		map[string]models.FieldMatch{"Company": {Mode: models.MatchExact, IgnoreCase: true}})) == 2)
+	assert.True(t, len(search(&models.Metadata{Title: "Azure"},
+		map[string]models.FieldMatch{"Title": {Mode: models.MatchPrefix}})) == 1)
+	assert.True(t, len(search(&models.Metadata{Title: "azure"},
+		map[string]models.FieldMatch{"Title": {Mode: models.MatchPrefix}})) == 0)
+	assert.True(t, len(search(&models.Metadata{Title: "azure"},
+		map[string]models.FieldMatch{"Title": {Mode: models.MatchPrefix, IgnoreCase: true}})) == 1)
+	assert.True(t, len(search(&models.Metadata{Title: "*Service"},
+		map[string]models.FieldMatch{"Title": {Mode: models.MatchGlob}})) == 2)
+	assert.True(t, len(search(&models.Metadata{Title: "*service", Company: "m*"},
+		map[string]models.FieldMatch{"Title": {Mode: models.MatchGlob, IgnoreCase: true}, "Company": {Mode: models.MatchGlob, IgnoreCase: true}})) == 1)
+}
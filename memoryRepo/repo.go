@@ -2,21 +2,32 @@ package memoryrepo
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
 	"github.com/fatih/structs"
 	"github.com/google/uuid"
 	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/app"
 	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/query"
 	"gitlab.com/erikwu09/yamlr/utils"
 )
 
 type memoryRepository struct {
 	//memoryDB is a in-memory schema-based db that supports data indexing for optimized querying
 	memoryDb *memdb.MemDB
+	//wal is an optional write-ahead log; when set every write is journaled before it is committed
+	wal *writeAheadLog
+	//logger reports the failures of the write-ahead log that do not fail a write
+	logger *log.Logger
 }
 
 const metadataTable string = "metadata"
 const maintainerTable string = "maintainers"
+const maintainerLinkTable string = "maintainer_links"
+const revisionTable string = "revisions"
 
 var repo *memoryRepository
 
@@ -29,9 +40,55 @@ func GetMemoryRepository() (*memoryRepository, error) {
 		return repo, nil
 	}
 
+	r, err := newMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	repo = r
+
+	return repo, nil
+}
+
+// Returns a memoryRepository backed by a write-ahead log and snapshots stored in dataDir.
+// The log is compacted into a new snapshot every compactEvery writes
+func GetDurableMemoryRepository(dataDir string, compactEvery int, logger *log.Logger) (*memoryRepository, error) {
+
+	if repo != nil {
+		return repo, nil
+	}
+
+	r, err := newDurableMemoryRepository(dataDir, compactEvery, logger)
+	if err != nil {
+		return nil, err
+	}
+	repo = r
+
+	return repo, nil
+}
+
+func newDurableMemoryRepository(dataDir string, compactEvery int, logger *log.Logger) (*memoryRepository, error) {
+	r, err := newMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+	wal, err := openWriteAheadLog(dataDir, compactEvery)
+	if err != nil {
+		return nil, err
+	}
+	if err = wal.replay(r.restore); err != nil {
+		wal.close()
+		return nil, err
+	}
+	r.wal = wal
+	r.logger = logger
+	return r, nil
+}
+
+func newMemoryRepository() (*memoryRepository, error) {
 	var table = make(map[string]*memdb.TableSchema)
 
-	// set up search indexes for metadata table
+	// set up search indexes for metadata table. Rule sets may make any field optional so the indexes of the
+	// fields of the document allow missing values
 	var metadataIndexes = make(map[string]*memdb.IndexSchema)
 
 	metadataIndexes["id"] = &memdb.IndexSchema{
@@ -41,39 +98,74 @@ func GetMemoryRepository() (*memoryRepository, error) {
 	}
 
 	metadataIndexes["Title"] = &memdb.IndexSchema{
-		Name:    "Title",
-		Unique:  false,
-		Indexer: &memdb.StringFieldIndex{Field: "Title"},
+		Name:         "Title",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Title"},
 	}
 
 	metadataIndexes["Company"] = &memdb.IndexSchema{
-		Name:    "Company",
-		Unique:  false,
-		Indexer: &memdb.StringFieldIndex{Field: "Company"},
+		Name:         "Company",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Company"},
+	}
+
+	metadataIndexes["TitleLower"] = &memdb.IndexSchema{
+		Name:         "TitleLower",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Title", Lowercase: true},
+	}
+
+	metadataIndexes["CompanyLower"] = &memdb.IndexSchema{
+		Name:         "CompanyLower",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Company", Lowercase: true},
 	}
 
 	metadataIndexes["Website"] = &memdb.IndexSchema{
-		Name:    "Website",
-		Unique:  false,
-		Indexer: &memdb.StringFieldIndex{Field: "Website", Lowercase: true},
+		Name:         "Website",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Website", Lowercase: true},
 	}
 
 	metadataIndexes["Source"] = &memdb.IndexSchema{
-		Name:    "Source",
-		Unique:  false,
-		Indexer: &memdb.StringFieldIndex{Field: "Source", Lowercase: true},
+		Name:         "Source",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Source", Lowercase: true},
 	}
 
 	metadataIndexes["License"] = &memdb.IndexSchema{
-		Name:    "License",
-		Unique:  false,
-		Indexer: &memdb.StringFieldIndex{Field: "License"},
+		Name:         "License",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      licenseIndex{},
 	}
 
-	metadataIndexes["MaintainerIDs"] = &memdb.IndexSchema{
-		Name:    "MaintainerIDs",
-		Unique:  false,
-		Indexer: &memdb.StringSliceFieldIndex{Field: "MaintainerIDs"},
+	metadataIndexes["LicenseLower"] = &memdb.IndexSchema{
+		Name:         "LicenseLower",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "License", Lowercase: true},
+	}
+
+	metadataIndexes["Version"] = &memdb.IndexSchema{
+		Name:         "Version",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Version"},
+	}
+
+	// versions ordered by precedence, used by range queries
+	metadataIndexes["VersionKey"] = &memdb.IndexSchema{
+		Name:         "VersionKey",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "VersionKey"},
 	}
 
 	table[metadataTable] = &memdb.TableSchema{
@@ -91,9 +183,10 @@ func GetMemoryRepository() (*memoryRepository, error) {
 	}
 
 	maintainerIndexes["Name"] = &memdb.IndexSchema{
-		Name:    "Name",
-		Unique:  false,
-		Indexer: &memdb.StringFieldIndex{Field: "Name"},
+		Name:         "Name",
+		Unique:       false,
+		AllowMissing: true,
+		Indexer:      &memdb.StringFieldIndex{Field: "Name"},
 	}
 
 	maintainerIndexes["Email"] = &memdb.IndexSchema{
@@ -108,6 +201,131 @@ func GetMemoryRepository() (*memoryRepository, error) {
 		Indexes: maintainerIndexes,
 	}
 
+	//set up the join table between metadata and maintainers
+	var maintainerLinkIndexes = make(map[string]*memdb.IndexSchema)
+
+	maintainerLinkIndexes["id"] = &memdb.IndexSchema{
+		Name:   "id",
+		Unique: true,
+		Indexer: &memdb.CompoundIndex{Indexes: []memdb.Indexer{
+			&memdb.StringFieldIndex{Field: "MetadataId"},
+			&memdb.StringFieldIndex{Field: "MaintainerId"},
+		}},
+	}
+
+	maintainerLinkIndexes["MetadataId"] = &memdb.IndexSchema{
+		Name:    "MetadataId",
+		Unique:  false,
+		Indexer: &memdb.StringFieldIndex{Field: "MetadataId"},
+	}
+
+	maintainerLinkIndexes["MaintainerId"] = &memdb.IndexSchema{
+		Name:    "MaintainerId",
+		Unique:  false,
+		Indexer: &memdb.StringFieldIndex{Field: "MaintainerId"},
+	}
+
+	table[maintainerLinkTable] = &memdb.TableSchema{
+		Name:    maintainerLinkTable,
+		Indexes: maintainerLinkIndexes,
+	}
+
+	//set up the revision history of metadata
+	var revisionIndexes = make(map[string]*memdb.IndexSchema)
+
+	revisionIndexes["id"] = &memdb.IndexSchema{
+		Name:   "id",
+		Unique: true,
+		Indexer: &memdb.CompoundIndex{Indexes: []memdb.Indexer{
+			&memdb.StringFieldIndex{Field: "MetadataId"},
+			&memdb.IntFieldIndex{Field: "Number"},
+		}},
+	}
+
+	revisionIndexes["MetadataId"] = &memdb.IndexSchema{
+		Name:    "MetadataId",
+		Unique:  false,
+		Indexer: &memdb.StringFieldIndex{Field: "MetadataId"},
+	}
+
+	table[revisionTable] = &memdb.TableSchema{
+		Name:    revisionTable,
+		Indexes: revisionIndexes,
+	}
+
+	//set up the inverted index over descriptions
+	var postingIndexes = make(map[string]*memdb.IndexSchema)
+
+	postingIndexes["id"] = &memdb.IndexSchema{
+		Name:   "id",
+		Unique: true,
+		Indexer: &memdb.CompoundIndex{Indexes: []memdb.Indexer{
+			&memdb.StringFieldIndex{Field: "MetadataId"},
+			&memdb.StringFieldIndex{Field: "Term"},
+		}},
+	}
+
+	postingIndexes["Term"] = &memdb.IndexSchema{
+		Name:    "Term",
+		Unique:  false,
+		Indexer: &memdb.StringFieldIndex{Field: "Term"},
+	}
+
+	postingIndexes["MetadataId"] = &memdb.IndexSchema{
+		Name:    "MetadataId",
+		Unique:  false,
+		Indexer: &memdb.StringFieldIndex{Field: "MetadataId"},
+	}
+
+	table[postingTable] = &memdb.TableSchema{
+		Name:    postingTable,
+		Indexes: postingIndexes,
+	}
+
+	//set up the trigram index used by fuzzy matching
+	var trigramIndexes = make(map[string]*memdb.IndexSchema)
+
+	trigramIndexes["id"] = &memdb.IndexSchema{
+		Name:   "id",
+		Unique: true,
+		Indexer: &memdb.CompoundIndex{Indexes: []memdb.Indexer{
+			&memdb.StringFieldIndex{Field: "Field"},
+			&memdb.StringFieldIndex{Field: "Trigram"},
+			&memdb.StringFieldIndex{Field: "MetadataId"},
+		}},
+	}
+
+	trigramIndexes["Trigram"] = &memdb.IndexSchema{
+		Name:   "Trigram",
+		Unique: false,
+		Indexer: &memdb.CompoundIndex{Indexes: []memdb.Indexer{
+			&memdb.StringFieldIndex{Field: "Field"},
+			&memdb.StringFieldIndex{Field: "Trigram"},
+		}},
+	}
+
+	trigramIndexes["MetadataId"] = &memdb.IndexSchema{
+		Name:    "MetadataId",
+		Unique:  false,
+		Indexer: &memdb.StringFieldIndex{Field: "MetadataId"},
+	}
+
+	table[trigramTable] = &memdb.TableSchema{
+		Name:    trigramTable,
+		Indexes: trigramIndexes,
+	}
+
+	table[textStatsTable] = &memdb.TableSchema{
+		Name: textStatsTable,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": &memdb.IndexSchema{
+				Name:    "id",
+				Unique:  true,
+				Indexer: &memdb.StringFieldIndex{Field: "Id"},
+			},
+		},
+	}
+
 	schema = &memdb.DBSchema{Tables: table}
 
 	cache, err := memdb.NewMemDB(schema)
@@ -115,9 +333,15 @@ func GetMemoryRepository() (*memoryRepository, error) {
 		return nil, err
 	}
 
-	repo = &memoryRepository{memoryDb: cache}
+	return &memoryRepository{memoryDb: cache}, nil
+}
 
-	return repo, nil
+// Close flushes and closes the write-ahead log, if any
+func (r *memoryRepository) Close() error {
+	if r.wal == nil {
+		return nil
+	}
+	return r.wal.close()
 }
 
 func (r *memoryRepository) Get(id uuid.UUID) (metadata *models.Metadata, err error) {
@@ -131,54 +355,215 @@ func (r *memoryRepository) Get(id uuid.UUID) (metadata *models.Metadata, err err
 	return &result.Metadata, nil
 }
 
-func (r *memoryRepository) Insert(metadata *models.Metadata) (id uuid.UUID, err error) {
+func (r *memoryRepository) Insert(metadata *models.Metadata, author string) (id uuid.UUID, err error) {
 	tx := r.memoryDb.Txn(true)
-	defer abortOrCommit(err, tx)
+	defer func() { abortOrCommit(err, tx) }()
 	//first get maintainers
+	if err = checkMaintainers(metadata.Maintainers); err != nil {
+		return
+	}
 	id = uuid.New()
-	_, err = r.insertMetadata(metadata, tx, id)
+	timestamp := time.Now().UTC()
+	if _, err = r.insertMetadata(metadata, tx, id, timestamp, author); err != nil {
+		return id, err
+	}
+	err = r.journal(walEntry{Op: walInsert, Id: id.String(), Timestamp: timestamp, Author: author, Metadata: metadata}, tx)
 	return id, err
 }
 
-func (r *memoryRepository) Update(id uuid.UUID, metadata *models.Metadata) (err error) {
+func (r *memoryRepository) Update(id uuid.UUID, metadata *models.Metadata, expectedRevision int, author string) (revision int, err error) {
 	tx := r.memoryDb.Txn(true)
-	defer abortOrCommit(err, tx)
+	defer func() { abortOrCommit(err, tx) }()
 
-	result, err := r.getMetadata(id, tx)
+	if err = checkMaintainers(metadata.Maintainers); err != nil {
+		return
+	}
+	current, err := r.getMetadata(id, tx)
+	if err != nil {
+		return
+	}
+	if err = checkRevision(current, expectedRevision); err != nil {
+		return
+	}
+	timestamp := time.Now().UTC()
+	metadataDAO, err := r.insertMetadata(metadata, tx, id, timestamp, author)
+	if err != nil {
+		return
+	}
+	if err = r.journal(walEntry{Op: walUpdate, Id: id.String(), Timestamp: timestamp, Author: author, Metadata: metadata}, tx); err != nil {
+		return
+	}
+	return metadataDAO.Revision, nil
+}
+
+func (r *memoryRepository) Delete(id uuid.UUID, expectedRevision int, author string) (err error) {
+	tx := r.memoryDb.Txn(true)
+	defer func() { abortOrCommit(err, tx) }()
+
+	current, err := r.getMetadata(id, tx)
 	if err != nil {
 		return
 	}
-	if result == nil {
+	if err = checkRevision(current, expectedRevision); err != nil {
 		return
 	}
-	// we can also just change the reference of the returned pointer to the new model
-	tx.Delete(metadataTable, result)
+	timestamp := time.Now().UTC()
+	if err = r.deleteMetadata(id, tx, timestamp, author); err != nil {
+		return
+	}
+	err = r.journal(walEntry{Op: walDelete, Id: id.String(), Timestamp: timestamp, Author: author}, tx)
+	return
+}
+
+// checkRevision fails with a PreconditionFailedError unless expectedRevision is 0 or the current revision.
+// The check happens inside the write transaction so no other write can slip in between
+func checkRevision(current *MetadataDAO, expectedRevision int) error {
+	if expectedRevision != 0 && expectedRevision != current.Revision {
+		return app.PreconditionFailedError{Reason: fmt.Sprintf("expected revision %d but current revision is %d", expectedRevision, current.Revision)}
+	}
+	return nil
+}
 
-	_, err = r.insertMetadata(metadata, tx, id)
+// journal appends a write to the write-ahead log while the write transaction is still held,
+// so the order of the log matches the order in which transactions are committed
+func (r *memoryRepository) journal(entry walEntry, tx *memdb.Txn) error {
+	if r.wal == nil {
+		return nil
+	}
+	if err := r.wal.append(entry); err != nil {
+		return err
+	}
+	if !r.wal.shouldCompact() {
+		return nil
+	}
+	// the snapshot has to include the writes of the current transaction.
+	// the entry is already durable at this point so a failed compaction is logged and retried later
+	// instead of failing the current one
+	snapshot, err := r.dump(tx)
+	if err == nil {
+		err = r.wal.compact(snapshot)
+	}
+	if err != nil {
+		r.wal.postponeCompaction()
+		r.logger.Printf("compacting the write-ahead log failed, retrying in %d writes: %v", r.wal.compactEvery, err)
+	}
+	return nil
+}
+
+// restore re-applies a journaled write without journaling it again
+func (r *memoryRepository) restore(entry walEntry) (err error) {
+	id, err := uuid.Parse(entry.Id)
+	if err != nil {
+		return err
+	}
+	tx := r.memoryDb.Txn(true)
+	defer func() { abortOrCommit(err, tx) }()
+
+	if entry.Op == walDelete {
+		err = r.deleteMetadata(id, tx, entry.Timestamp, entry.Author)
+		return
+	}
+	_, err = r.insertMetadata(entry.Metadata, tx, id, entry.Timestamp, entry.Author)
 	return
 }
 
-func (r *memoryRepository) Search(metadata *models.Metadata) (results []models.Metadata, err error) {
-	params := getQueryParams(*metadata)
-	temp, err := r.queryByParams(params)
+// dump returns the revision history of every metadata, deleted ones included, as a list of writes
+func (r *memoryRepository) dump(tx *memdb.Txn) ([]walEntry, error) {
+	it, err := tx.Get(revisionTable, "MetadataId")
 	if err != nil {
 		return nil, err
 	}
+	// the index is ordered by metadata id so the revisions of a metadata are next to each other
+	ids := make([]string, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		revisionDAO, k := obj.(*RevisionDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		if len(ids) == 0 || ids[len(ids)-1] != revisionDAO.MetadataId {
+			ids = append(ids, revisionDAO.MetadataId)
+		}
+	}
+	entries := make([]walEntry, 0)
+	for _, id := range ids {
+		revisions, err := r.getRevisions(id, tx)
+		if err != nil {
+			return nil, err
+		}
+		for _, revision := range revisions {
+			entry := walEntry{Op: walUpdate, Id: id, Timestamp: revision.Timestamp, Author: revision.Author}
+			switch {
+			case revision.Deleted:
+				entry.Op = walDelete
+			case revision.Number == 1:
+				entry.Op = walInsert
+			}
+			if !revision.Deleted {
+				metadata := revision.Metadata
+				entry.Metadata = &metadata
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *memoryRepository) Search(criteria app.SearchCriteria) (results []models.Metadata, total int, err error) {
+	params := getSearchParams(criteria)
+	tx := r.memoryDb.Txn(false)
+	defer tx.Abort()
+	temp, scores, err := r.queryByParamsTx(params, tx)
+	if err != nil {
+		return nil, 0, err
+	}
+	sortResults(temp, scores, criteria.Sort)
+	total = len(temp)
+	temp = paginate(temp, criteria.Offset, criteria.Limit)
 	results = make([]models.Metadata, 0, len(temp))
 	for _, result := range temp {
-		results = append(results, *result)
+		results = append(results, result.Metadata)
 	}
 	return
 }
 
+// getSearchParams returns the query parameters of the filters of the criteria
+func getSearchParams(criteria app.SearchCriteria) map[string]interface{} {
+	params := getQueryParams(*criteria.Metadata)
+	for field, match := range criteria.Match {
+		if value, k := params[field].(string); k && value != "" {
+			params[field] = fieldQuery{value: value, match: match}
+		}
+	}
+	if match, k := criteria.Match["Name"]; k {
+		names := make([]fieldQuery, 0)
+		for _, m := range criteria.Metadata.Maintainers {
+			if m != nil && m.Name != "" {
+				names = append(names, fieldQuery{value: m.Name, match: match})
+			}
+		}
+		if len(names) > 0 {
+			params["Name"] = names
+		}
+	}
+	if criteria.Expr != nil {
+		params["Query"] = criteria.Expr
+	}
+	return params
+}
+
 func getQueryParams(metadata models.Metadata) map[string]interface{} {
 	params := structs.Map(metadata)
 	if metadata.Maintainers != nil && len(metadata.Maintainers) > 0 {
 		emails := make([]string, 0, len(metadata.Maintainers))
 		for _, m := range metadata.Maintainers {
-			emails = append(emails, m.Email)
+			// maintainers may only be searched by name
+			if m != nil && m.Email != "" {
+				emails = append(emails, m.Email)
+			}
+		}
+		if len(emails) > 0 {
+			params["Email"] = emails
 		}
-		params["Email"] = emails
 	}
 	if _, k := params["Maintainers"]; k {
 		delete(params, "Maintainers")
@@ -186,11 +571,17 @@ func getQueryParams(metadata models.Metadata) map[string]interface{} {
 	return params
 }
 
+func (r *memoryRepository) queryByParams(params map[string]interface{}) (results []*MetadataDAO, err error) {
+	tx := r.memoryDb.Txn(false)
+	defer tx.Abort()
+	results, _, err = r.queryByParamsTx(params, tx)
+	return
+}
+
 // TODO: refactor this
-func (r *memoryRepository) queryByParams(params map[string]interface{}) (results []*models.Metadata, err error) {
+// scores holds the relevance of each result when the search is scored (i.e. free text or fuzzy matches)
+func (r *memoryRepository) queryByParamsTx(params map[string]interface{}, tx *memdb.Txn) (results []*MetadataDAO, scores map[*MetadataDAO]float64, err error) {
 	var resultSet *utils.Set
-	tx := r.memoryDb.Txn(true)
-	defer abortOrCommit(err, tx)
 	for index, queryVal := range params {
 		if isQueryValEmptyOrNull(queryVal) {
 			continue
@@ -198,6 +589,36 @@ func (r *memoryRepository) queryByParams(params map[string]interface{}) (results
 		var temp *utils.Set
 		if index == "Email" {
 			temp, err = r.queryByEmails(queryVal.([]string), tx)
+		} else if index == "Description" {
+			var descriptionScores map[*MetadataDAO]float64
+			temp, descriptionScores, err = r.queryByDescription(queryVal.(string), tx)
+			scores = mergeScores(scores, descriptionScores)
+		} else if index == "Version" {
+			temp, err = r.queryByVersion(queryVal.(string), tx)
+		} else if index == "Query" {
+			temp, err = r.queryByExpr(queryVal.(query.Expr), tx, &scores)
+		} else if q, k := queryVal.(fieldQuery); k && q.match.Mode == models.MatchFuzzy {
+			var fuzzyScores map[*MetadataDAO]float64
+			temp, fuzzyScores, err = r.queryByFuzzy(index, q, tx)
+			scores = mergeScores(scores, fuzzyScores)
+		} else if q, k := queryVal.(fieldQuery); k {
+			temp, err = r.queryByMatch(index, q, tx)
+		} else if names, k := queryVal.([]fieldQuery); k {
+			// every maintainer name has to match
+			for _, name := range names {
+				var nameSet *utils.Set
+				var fuzzyScores map[*MetadataDAO]float64
+				nameSet, fuzzyScores, err = r.queryByFuzzy(index, name, tx)
+				if err != nil {
+					break
+				}
+				scores = mergeScores(scores, fuzzyScores)
+				if temp == nil {
+					temp = nameSet
+				} else {
+					temp = temp.Intersect(*nameSet)
+				}
+			}
 		} else {
 			temp, err = r.query(index, queryVal, tx)
 		}
@@ -215,11 +636,33 @@ func (r *memoryRepository) queryByParams(params map[string]interface{}) (results
 		return
 	}
 	for result := range *resultSet {
-		results = append(results, result.(*models.Metadata))
+		results = append(results, result.(*MetadataDAO))
 	}
 	return
 }
 
+func (r *memoryRepository) queryByDescription(text string, tx *memdb.Txn) (*utils.Set, map[*MetadataDAO]float64, error) {
+	idScores, err := r.searchDescription(text, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := utils.NewSet()
+	scores := make(map[*MetadataDAO]float64, len(idScores))
+	for id, score := range idScores {
+		res, err := tx.First(metadataTable, "id", id)
+		if err != nil {
+			return nil, nil, err
+		}
+		metadata, k := res.(*MetadataDAO)
+		if !k {
+			return nil, nil, errors.New("something went wrong")
+		}
+		result.Add(metadata)
+		scores[metadata] = score
+	}
+	return result, scores, nil
+}
+
 func (r *memoryRepository) query(index string, queryVal interface{}, tx *memdb.Txn) (*utils.Set, error) {
 	result := utils.NewSet()
 	it, err := tx.Get(metadataTable, index, queryVal)
@@ -231,7 +674,7 @@ func (r *memoryRepository) query(index string, queryVal interface{}, tx *memdb.T
 		if !k {
 			return nil, errors.New("something went wrong")
 		}
-		result.Add(&(metadata.Metadata))
+		result.Add(metadata)
 	}
 	return result, nil
 }
@@ -277,23 +720,32 @@ func (r *memoryRepository) queryByEmails(emails []string, tx *memdb.Txn) (result
 		if err != nil {
 			return nil, err
 		}
-		results.Add(&(metadata.Metadata))
+		results.Add(metadata)
 	}
 	return
 }
 
+// queryByEmail returns the ids of the metadata maintained by the email, which is matched by normalized address
+// so that search terms like <Erik.Wu@microsoft.com> match the stored erik.wu@microsoft.com
 func (r *memoryRepository) queryByEmail(email string, tx *memdb.Txn) (*utils.Set, error) {
-	obj, err := tx.First(maintainerTable, "Email", email)
+	obj, err := tx.First(maintainerTable, "Email", app.NormalizeEmail(email))
 	if err != nil {
 		return nil, err
 	}
+	results := utils.NewSet()
+	if obj == nil {
+		return results, nil
+	}
 	maintainer, k := obj.(*MaintainerDAO)
 	if !k {
 		return nil, errors.New("something went wrong")
 	}
-	results := utils.NewSet()
-	for _, metadataID := range maintainer.MetadataIds {
-		results.Add(metadataID)
+	links, err := r.getMaintainerLinks("MaintainerId", maintainer.Id, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		results.Add(link.MetadataId)
 	}
 
 	return results, nil
@@ -306,7 +758,7 @@ func (r *memoryRepository) getMetadata(id uuid.UUID, tx *memdb.Txn) (metadataDAO
 		return
 	}
 	if res == nil {
-		return nil, errors.New("no metadata found")
+		return nil, app.NotFoundError{Reason: fmt.Sprintf("no metadata found with id %s", id)}
 	}
 	metadataDAO, k := res.(*MetadataDAO)
 	if !k {
@@ -315,53 +767,65 @@ func (r *memoryRepository) getMetadata(id uuid.UUID, tx *memdb.Txn) (metadataDAO
 	return
 }
 
-func (r *memoryRepository) addNewMaintainer(maintainer *models.Maintainer, metadataID *uuid.UUID, tx *memdb.Txn) (*MaintainerDAO, error) {
-	maintainerID := uuid.New()
-	maintainerDAO := &MaintainerDAO{Maintainer: *maintainer, Id: maintainerID.String()}
-	if metadataID != nil {
-		maintainerDAO.MetadataIds = append(maintainerDAO.MetadataIds, metadataID.String())
-	}
-	err := tx.Insert(maintainerTable, maintainerDAO)
+// deleteMetadata removes the metadata and its maintainer links, and records a tombstone revision.
+// Maintainers that no longer maintain any metadata are removed as well, the revision history is kept
+func (r *memoryRepository) deleteMetadata(id uuid.UUID, tx *memdb.Txn, timestamp time.Time, author string) error {
+	metadataDAO, err := r.getMetadata(id, tx)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if err := tx.Delete(metadataTable, metadataDAO); err != nil {
+		return err
+	}
+	tombstone := &RevisionDAO{
+		Revision:   models.Revision{Number: metadataDAO.Revision + 1, Timestamp: timestamp, Author: author, Deleted: true},
+		MetadataId: metadataDAO.Id,
 	}
-	return maintainerDAO, nil
+	if err := tx.Insert(revisionTable, tombstone); err != nil {
+		return err
+	}
+	if err := r.unindexDescription(metadataDAO, tx); err != nil {
+		return err
+	}
+	if _, err := tx.DeleteAll(trigramTable, "MetadataId", metadataDAO.Id); err != nil {
+		return err
+	}
+	return r.linkMaintainers(metadataDAO.Id, nil, tx)
 }
 
-func (r *memoryRepository) insertMetadata(metadata *models.Metadata, tx *memdb.Txn, id uuid.UUID) (metadataDAO *MetadataDAO, err error) {
-	//first get maintainers
-	maintainerIds := make([]string, 0)
-	for _, m := range metadata.Maintainers {
-		var res interface{}
-		res, err = tx.First(maintainerTable, "Email", m.Email)
-		if err != nil {
-			return
-		}
-		var maintainerID string
-		if res == nil {
-			var maintainerDAO *MaintainerDAO
-			maintainerDAO, err = r.addNewMaintainer(m, &id, tx)
-			if err != nil {
-				return
-			}
-			maintainerID = maintainerDAO.Id
-		} else {
-			maintainer, k := res.(*MaintainerDAO)
-			if !k {
-				return nil, errors.New("something went wrong")
-			}
-			maintainer.MetadataIds = append(maintainer.MetadataIds, id.String())
-			maintainerID = maintainer.Id
+// insertMetadata inserts the metadata, or replaces it if it already exists, links it to exactly
+// the maintainers listed in the document and records it as a new revision
+func (r *memoryRepository) insertMetadata(metadata *models.Metadata, tx *memdb.Txn, id uuid.UUID, timestamp time.Time, author string) (metadataDAO *MetadataDAO, err error) {
+	metadataDAO = &MetadataDAO{Metadata: copyMetadata(metadata), Id: id.String(), Revision: 1, CreatedAt: timestamp, UpdatedAt: timestamp, UpdatedBy: author}
+	metadataDAO.VersionKey = versionKey(metadataDAO.Version)
+	existing, err := tx.First(metadataTable, "id", metadataDAO.Id)
+	if err != nil {
+		return nil, err
+	}
+	var previous *MetadataDAO
+	if existing != nil {
+		var k bool
+		previous, k = existing.(*MetadataDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
 		}
-		maintainerIds = append(maintainerIds, maintainerID)
+		metadataDAO.Revision = previous.Revision + 1
+		metadataDAO.CreatedAt = previous.CreatedAt
 	}
-
-	//persist
-	metadataDAO = &MetadataDAO{Metadata: *metadata, Id: id.String(), MaintainerIDs: maintainerIds}
-
-	err = tx.Insert(metadataTable, metadataDAO)
-	if err != nil {
-		return
+	if err = r.indexDescription(metadataDAO, previous, tx); err != nil {
+		return nil, err
+	}
+	if err = r.indexTrigrams(metadataDAO, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Insert(metadataTable, metadataDAO); err != nil {
+		return nil, err
+	}
+	if err = r.linkMaintainers(metadataDAO.Id, metadata.Maintainers, tx); err != nil {
+		return nil, err
+	}
+	if err = r.addRevision(metadataDAO, tx); err != nil {
+		return nil, err
 	}
 	return
 }
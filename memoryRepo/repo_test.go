@@ -1,11 +1,15 @@
 package memoryrepo
 
 import (
+	"strconv"
+	"strings"
+	"testing"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
 	"gitlab.com/erikwu09/yamlr/models"
-	"strconv"
-	"testing"
+	"gitlab.com/erikwu09/yamlr/query"
 )
 
 func Test_Setup(t *testing.T) {
@@ -18,7 +22,7 @@ func Test_Setup(t *testing.T) {
 func Test_InsertAndGet(t *testing.T) {
 	repo, _ := GetMemoryRepository()
 	metadata := dummyMetadata("", "", "")
-	id, err := repo.Insert(metadata)
+	id, err := repo.Insert(metadata, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -33,13 +37,13 @@ func Test_InsertAndGet(t *testing.T) {
 func Test_InsertAndUpdate(t *testing.T) {
 	repo, _ := GetMemoryRepository()
 	metadata := dummyMetadata("", "", "")
-	id, err := repo.Insert(metadata)
+	id, err := repo.Insert(metadata, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	assert.NotNil(t, id)
 	newMetadata := dummyMetadata("new", "", "")
-	err = repo.Update(id, newMetadata)
+	_, err = repo.Update(id, newMetadata, 0, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -54,13 +58,13 @@ func Test_InsertAndUpdate(t *testing.T) {
 func Test_InsertMultiple(t *testing.T) {
 	repo, _ := GetMemoryRepository()
 	metadata := dummyMetadata("", "", "")
-	id1, err := repo.Insert(metadata)
+	id1, err := repo.Insert(metadata, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	assert.NotNil(t, id1)
 	newMetadata := dummyMetadata("new", "", "")
-	id2, err := repo.Insert(newMetadata)
+	id2, err := repo.Insert(newMetadata, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -72,13 +76,15 @@ func Test_HandleNonExistingMetadata(t *testing.T) {
 	result, err := repo.Get(uuid.New())
 	assert.Error(t, err)
 	assert.Nil(t, result)
+	_, k := err.(app.NotFoundError)
+	assert.True(t, k)
 }
 
 func Test_QueryMetadata(t *testing.T) {
 	repo, _ := GetMemoryRepository()
 	for i := 0; i < 5; i++ {
 		metadata := dummyMetadata(strconv.Itoa(i), "", "")
-		repo.Insert(metadata)
+		repo.Insert(metadata, "")
 	}
 	queryParams := map[string]interface{}{"Title": "dummyTitle1"}
 	results, err := repo.queryByParams(queryParams)
@@ -98,12 +104,12 @@ func Test_QueryMetadata(t *testing.T) {
 
 func Test_QueryMultipleFields(t *testing.T) {
 	repo, _ := GetMemoryRepository()
-	repo.Insert(dummyMetadata("", "", ""))
-	repo.Insert(dummyMetadata("titleSuffix", "companySuffix", ""))
-	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix", ""))
-	repo.Insert(dummyMetadata("titleSuffix", "", ""))
-	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix", ""))
-	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix2", ""))
+	repo.Insert(dummyMetadata("", "", ""), "")
+	repo.Insert(dummyMetadata("titleSuffix", "companySuffix", ""), "")
+	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix", ""), "")
+	repo.Insert(dummyMetadata("titleSuffix", "", ""), "")
+	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix", ""), "")
+	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix2", ""), "")
 
 	queryParams := map[string]interface{}{"Title": "dummyTitle" + "titleSuffix2",
 		"Company": "dummyCompany" + "companySuffix"}
@@ -116,11 +122,11 @@ func Test_QueryMultipleFields(t *testing.T) {
 func Test_QueryByEmails(t *testing.T) {
 	repo, _ := GetMemoryRepository()
 	expectedResult := dummyMetadata("", "", "microsoft.com")
-	repo.Insert(expectedResult)
-	repo.Insert(dummyMetadata("", "", "amazon.com"))
-	repo.Insert(dummyMetadata("", "", "google.com"))
-	repo.Insert(dummyMetadata("", "", "apple.com"))
-	repo.Insert(dummyMetadata("", "", "microsoft.com"))
+	repo.Insert(expectedResult, "")
+	repo.Insert(dummyMetadata("", "", "amazon.com"), "")
+	repo.Insert(dummyMetadata("", "", "google.com"), "")
+	repo.Insert(dummyMetadata("", "", "apple.com"), "")
+	repo.Insert(dummyMetadata("", "", "microsoft.com"), "")
 
 	tx := repo.memoryDb.Txn(false)
 	defer tx.Abort()
@@ -129,18 +135,18 @@ func Test_QueryByEmails(t *testing.T) {
 	assert.NotNil(t, results)
 	resultList := results.ToSlice()
 	assert.True(t, len(resultList) == 2)
-	assert.Equal(t, expectedResult, resultList[0])
+	assert.Equal(t, *expectedResult, resultList[0].(*MetadataDAO).Metadata)
 }
 
 func Test_QueryByMultipleEmails(t *testing.T) {
 	repo, _ := GetMemoryRepository()
 	expectedResult := dummyMetadata("", "", "microsoft.com")
 	expectedResult.Maintainers = append(expectedResult.Maintainers, &models.Maintainer{Name: "erik wu", Email: "erw@microsoft.com"})
-	repo.Insert(expectedResult)
-	repo.Insert(dummyMetadata("", "", "amazon.com"))
-	repo.Insert(dummyMetadata("", "", "google.com"))
-	repo.Insert(dummyMetadata("", "", "apple.com"))
-	repo.Insert(dummyMetadata("", "", "microsoft.com"))
+	repo.Insert(expectedResult, "")
+	repo.Insert(dummyMetadata("", "", "amazon.com"), "")
+	repo.Insert(dummyMetadata("", "", "google.com"), "")
+	repo.Insert(dummyMetadata("", "", "apple.com"), "")
+	repo.Insert(dummyMetadata("", "", "microsoft.com"), "")
 
 	tx := repo.memoryDb.Txn(false)
 	defer tx.Abort()
@@ -153,16 +159,16 @@ func Test_QueryByMultipleEmails(t *testing.T) {
 
 func Test_QueryByMetadataAndMaintainer(t *testing.T) {
 	repo, _ := GetMemoryRepository()
-	repo.Insert(dummyMetadata("", "", ""))
+	repo.Insert(dummyMetadata("", "", ""), "")
 	//should return this
 	expectedResult1 := dummyMetadata("expectedResult1", "microsoft", "microsoft.com")
-	repo.Insert(expectedResult1)
-	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix", "aol.com"))
-	repo.Insert(dummyMetadata("titleSuffix", "", "amazon.com"))
+	repo.Insert(expectedResult1, "")
+	repo.Insert(dummyMetadata("titleSuffix2", "companySuffix", "aol.com"), "")
+	repo.Insert(dummyMetadata("titleSuffix", "", "amazon.com"), "")
 	//should return this
 	expectedResult2 := dummyMetadata("expectedResult2", "microsoft", "microsoft.com")
-	repo.Insert(expectedResult2)
-	repo.Insert(dummyMetadata("titleSuffix2", "apple", "apple.com"))
+	repo.Insert(expectedResult2, "")
+	repo.Insert(dummyMetadata("titleSuffix2", "apple", "apple.com"), "")
 	queryParams := map[string]interface{}{"Company": "dummyCompany" + "microsoft",
 		"Email": []string{"email@microsoft.com"}}
 
@@ -172,6 +178,268 @@ func Test_QueryByMetadataAndMaintainer(t *testing.T) {
 	assert.True(t, len(results) == 2)
 }
 
+func Test_Delete(t *testing.T) {
+	repo, _ := GetMemoryRepository()
+	shared := dummyMetadata("", "", "delete.com")
+	shared.Maintainers = append(shared.Maintainers, &models.Maintainer{Name: "owner", Email: "owner@delete.com"})
+	id1, _ := repo.Insert(shared, "")
+	id2, _ := repo.Insert(dummyMetadata("", "", "delete.com"), "")
+
+	err := repo.Delete(id1, 0, "")
+	assert.NoError(t, err)
+	result, err := repo.Get(id1)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+
+	tx := repo.memoryDb.Txn(false)
+	defer tx.Abort()
+	results, err := repo.queryByEmails([]string{"email@delete.com"}, tx)
+	assert.NoError(t, err)
+	resultList := results.ToSlice()
+	assert.True(t, len(resultList) == 1)
+	maintainer, err := tx.First(maintainerTable, "Email", "email@delete.com")
+	assert.NoError(t, err)
+	links, err := repo.getMaintainerLinks("MaintainerId", maintainer.(*MaintainerDAO).Id, tx)
+	assert.NoError(t, err)
+	assert.True(t, len(links) == 1)
+	assert.Equal(t, id2.String(), links[0].MetadataId)
+
+	// the maintainer only owned the deleted metadata
+	maintainer, err = tx.First(maintainerTable, "Email", "owner@delete.com")
+	assert.NoError(t, err)
+	assert.Nil(t, maintainer)
+	results, err = repo.queryByEmails([]string{"owner@delete.com"}, tx)
+	assert.NoError(t, err)
+	assert.True(t, len(results.ToSlice()) == 0)
+}
+
+func Test_UpdateMaintainers(t *testing.T) {
+	repo, _ := GetMemoryRepository()
+	metadata := dummyMetadata("", "", "update.com")
+	metadata.Maintainers = append(metadata.Maintainers, &models.Maintainer{Name: "dropped", Email: "dropped@update.com"})
+	id, _ := repo.Insert(metadata, "")
+
+	updated := dummyMetadata("", "", "update.com")
+	updated.Maintainers = append(updated.Maintainers, &models.Maintainer{Name: "added", Email: "added@update.com"})
+	_, err := repo.Update(id, updated, 0, "")
+	assert.NoError(t, err)
+	_, err = repo.Update(id, updated, 0, "")
+	assert.NoError(t, err)
+
+	tx := repo.memoryDb.Txn(false)
+	defer tx.Abort()
+	links, err := repo.getMaintainerLinks("MetadataId", id.String(), tx)
+	assert.NoError(t, err)
+	assert.True(t, len(links) == 2)
+
+	results, err := repo.queryByEmails([]string{"added@update.com"}, tx)
+	assert.NoError(t, err)
+	assert.True(t, len(results.ToSlice()) == 1)
+	results, err = repo.queryByEmails([]string{"dropped@update.com"}, tx)
+	assert.NoError(t, err)
+	assert.True(t, len(results.ToSlice()) == 0)
+	dropped, err := tx.First(maintainerTable, "Email", "dropped@update.com")
+	assert.NoError(t, err)
+	assert.Nil(t, dropped)
+}
+
+func Test_DeleteNonExistingMetadata(t *testing.T) {
+	repo, _ := GetMemoryRepository()
+	err := repo.Delete(uuid.New(), 0, "")
+	assert.Error(t, err)
+}
+
+func Test_Revisions(t *testing.T) {
+	repo, _ := GetMemoryRepository()
+	original := dummyMetadata("", "", "revisions.com")
+	id, _ := repo.Insert(original, "erik")
+	updated := dummyMetadata("", "", "revisions.com")
+	updated.License = "MIT"
+	_, err := repo.Update(id, updated, 0, "jane")
+	assert.NoError(t, err)
+
+	revisions, err := repo.GetRevisions(id)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 2)
+	assert.Equal(t, 1, revisions[0].Number)
+	assert.Equal(t, *original, revisions[0].Metadata)
+	assert.Equal(t, 2, revisions[1].Number)
+	assert.Equal(t, *updated, revisions[1].Metadata)
+	assert.False(t, revisions[1].Timestamp.Before(revisions[0].Timestamp))
+	assert.Equal(t, "erik", revisions[0].Author)
+	assert.Equal(t, "jane", revisions[1].Author)
+	current, err := repo.GetCurrentRevision(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "jane", current.Author)
+
+	revision, err := repo.GetRevision(id, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "GNU", revision.Metadata.License)
+	_, err = repo.GetRevision(id, 3)
+	assert.Error(t, err)
+	_, k := err.(app.NotFoundError)
+	assert.True(t, k)
+
+	// the history outlives the metadata, closed by a tombstone
+	assert.NoError(t, repo.Delete(id, 0, "ci"))
+	_, err = repo.GetCurrentRevision(id)
+	assert.Error(t, err)
+	revisions, err = repo.GetRevisions(id)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 3)
+	assert.Equal(t, models.Revision{Number: 3, Timestamp: revisions[2].Timestamp, Author: "ci", Deleted: true}, revisions[2])
+	_, err = repo.GetRevisions(uuid.New())
+	_, k = err.(app.NotFoundError)
+	assert.True(t, k)
+}
+
+func Test_ConditionalUpdate(t *testing.T) {
+	repo, _ := GetMemoryRepository()
+	id, _ := repo.Insert(dummyMetadata("", "", ""), "")
+
+	revision, err := repo.Update(id, dummyMetadata("first", "", ""), 1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, revision)
+
+	// a concurrent writer that still expects revision 1 loses
+	_, err = repo.Update(id, dummyMetadata("second", "", ""), 1, "")
+	assert.Error(t, err)
+	_, k := err.(app.PreconditionFailedError)
+	assert.True(t, k)
+
+	current, err := repo.GetCurrentRevision(id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, current.Number)
+	assert.Equal(t, "dummyTitlefirst", current.Metadata.Title)
+
+	// so does a deletion
+	err = repo.Delete(id, 1, "")
+	_, k = err.(app.PreconditionFailedError)
+	assert.True(t, k)
+	_, err = repo.Get(id)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Delete(id, 2, ""))
+}
+
+func Test_DuplicateMaintainers(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	metadata := dummyMetadata("", "", "duplicates.com")
+	metadata.Maintainers = append(metadata.Maintainers, &models.Maintainer{Name: "again", Email: strings.ToUpper(metadata.Maintainers[0].Email)})
+	_, err := repo.Insert(metadata, "")
+	assert.Error(t, err)
+	_, k := err.(app.ConflictError)
+	assert.True(t, k)
+
+	id, err := repo.Insert(dummyMetadata("", "", "duplicates.com"), "")
+	assert.NoError(t, err)
+	_, err = repo.Update(id, metadata, 0, "")
+	assert.Equal(t, app.CodeConflict, app.ErrorCode(err))
+}
+
+func Test_QueryByExpr(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	aks := dummyMetadata("AKS", "Microsoft", "microsoft.com")
+	aks.License = "MIT"
+	cortana := dummyMetadata("Cortana", "Microsoft", "microsoft.com")
+	cortana.License = "MIT"
+	hadoop := dummyMetadata("Hadoop", "Microsoft", "google.com")
+	hadoop.License = "Apache-2.0"
+	ecs := dummyMetadata("ECS", "Amazon", "amazon.com")
+	ecs.License = "MIT"
+	for _, m := range []*models.Metadata{aks, cortana, hadoop, ecs} {
+		repo.Insert(m, "")
+	}
+
+	expr, err := query.Parse(`company:dummyCompanyMicrosoft AND (license:MIT OR license:Apache-2.0) AND NOT title:dummyTitleCortana`)
+	assert.NoError(t, err)
+	results, _, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{}, Expr: expr})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 2)
+
+	// the expression is combined with the fields of the search document
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{License: "MIT"}, Expr: expr})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 1)
+	assert.Equal(t, aks.Title, results[0].Title)
+
+	expr, err = query.Parse(`NOT email:email@microsoft.com`)
+	assert.NoError(t, err)
+	results, _, err = repo.Search(app.SearchCriteria{Metadata: &models.Metadata{}, Expr: expr})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 2)
+}
+
+func Test_InsertMetadataWithOptionalFields(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	// rule sets may leave any field of the document empty
+	metadata := &models.Metadata{Description: "a catalog entry without title, company, website, source, version or license",
+		Maintainers: []*models.Maintainer{&models.Maintainer{Name: "No Email"}, &models.Maintainer{Email: "noname@example.com"}}}
+
+	id, err := repo.Insert(metadata, "")
+	assert.NoError(t, err)
+	result, err := repo.Get(id)
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, result)
+	results, _, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Description: "catalog"}, Sort: []app.SortKey{{Field: app.SortTitle}}})
+	assert.NoError(t, err)
+	assert.True(t, len(results) == 1)
+	facets, total, err := repo.Facets(app.SearchCriteria{Metadata: &models.Metadata{}}, []string{"Title", "License", "Email"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Empty(t, facets["Title"])
+	assert.Equal(t, []models.FacetValue{{Value: "noname@example.com", Count: 1}}, facets["Email"])
+
+	metadata.Title = "titled"
+	_, err = repo.Update(id, metadata, 0, "")
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Delete(id, 0, ""))
+}
+
+func Test_SortAndPaginate(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	titles := []string{"b", "D", "a", "c", "e"}
+	ids := make([]uuid.UUID, 0)
+	for _, title := range titles {
+		metadata := dummyMetadata("", "", "")
+		metadata.Title = title
+		id, _ := repo.Insert(metadata, "")
+		ids = append(ids, id)
+	}
+	// touch "a" so that it is the most recently updated
+	metadata := dummyMetadata("", "", "")
+	metadata.Title = "a"
+	repo.Update(ids[2], metadata, 0, "")
+
+	search := func(sort []app.SortKey, offset int, limit int) ([]string, int) {
+		results, total, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Company: "dummyCompany"}, Sort: sort, Offset: offset, Limit: limit})
+		assert.NoError(t, err)
+		found := make([]string, 0)
+		for _, result := range results {
+			found = append(found, result.Title)
+		}
+		return found, total
+	}
+
+	// insertion order by default
+	found, total := search(nil, 0, 0)
+	assert.Equal(t, titles, found)
+	assert.Equal(t, 5, total)
+
+	found, total = search([]app.SortKey{{Field: app.SortTitle}}, 0, 2)
+	assert.Equal(t, []string{"a", "b"}, found)
+	assert.Equal(t, 5, total)
+	found, _ = search([]app.SortKey{{Field: app.SortTitle}}, 2, 2)
+	assert.Equal(t, []string{"c", "D"}, found)
+	found, _ = search([]app.SortKey{{Field: app.SortTitle}}, 4, 2)
+	assert.Equal(t, []string{"e"}, found)
+	found, total = search([]app.SortKey{{Field: app.SortTitle}}, 10, 2)
+	assert.Equal(t, []string{}, found)
+	assert.Equal(t, 5, total)
+
+	found, _ = search([]app.SortKey{{Field: app.SortUpdated, Descending: true}}, 0, 1)
+	assert.Equal(t, []string{"a"}, found)
+}
+
 func Test_ParamTranslation(t *testing.T) {
 	data := models.Metadata{Title: "title",
 		Version:     "1.0",
@@ -0,0 +1,105 @@
+package memoryrepo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+// GetRevisions returns the revision history of a metadata, which is kept after it is deleted
+func (r *memoryRepository) GetRevisions(id uuid.UUID) (revisions []models.Revision, err error) {
+	tx := r.memoryDb.Txn(false)
+	defer tx.Abort()
+
+	revisions, err = r.getRevisions(id.String(), tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, app.NotFoundError{Reason: fmt.Sprintf("no metadata found with id %s", id)}
+	}
+	return revisions, nil
+}
+
+func (r *memoryRepository) GetRevision(id uuid.UUID, number int) (revision *models.Revision, err error) {
+	tx := r.memoryDb.Txn(false)
+	defer tx.Abort()
+
+	res, err := tx.First(revisionTable, "id", id.String(), number)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, app.NotFoundError{Reason: fmt.Sprintf("no revision %d found for metadata %s", number, id)}
+	}
+	revisionDAO, k := res.(*RevisionDAO)
+	if !k {
+		return nil, errors.New("something went wrong")
+	}
+	return &revisionDAO.Revision, nil
+}
+
+func (r *memoryRepository) GetCurrentRevision(id uuid.UUID) (revision *models.Revision, err error) {
+	tx := r.memoryDb.Txn(false)
+	defer tx.Abort()
+
+	metadataDAO, err := r.getMetadata(id, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Revision{Number: metadataDAO.Revision, Timestamp: metadataDAO.UpdatedAt, Author: metadataDAO.UpdatedBy, Metadata: metadataDAO.Metadata}, nil
+}
+
+// getRevisions returns the revision history of a metadata, oldest first
+func (r *memoryRepository) getRevisions(metadataID string, tx *memdb.Txn) ([]models.Revision, error) {
+	it, err := tx.Get(revisionTable, "MetadataId", metadataID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]models.Revision, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		revisionDAO, k := obj.(*RevisionDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		revisions = append(revisions, revisionDAO.Revision)
+	}
+	// revision numbers are varint encoded in the index so they do not come back in numeric order
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Number < revisions[j].Number })
+	return revisions, nil
+}
+
+func (r *memoryRepository) addRevision(metadataDAO *MetadataDAO, tx *memdb.Txn) error {
+	revisionDAO := &RevisionDAO{
+		Revision: models.Revision{
+			Number:    metadataDAO.Revision,
+			Timestamp: metadataDAO.UpdatedAt,
+			Author:    metadataDAO.UpdatedBy,
+			Metadata:  copyMetadata(&metadataDAO.Metadata),
+		},
+		MetadataId: metadataDAO.Id,
+	}
+	return tx.Insert(revisionTable, revisionDAO)
+}
+
+// copyMetadata deep copies a metadata so stored revisions do not share maintainers with the caller
+func copyMetadata(metadata *models.Metadata) models.Metadata {
+	copied := *metadata
+	if metadata.Maintainers != nil {
+		copied.Maintainers = make([]*models.Maintainer, 0, len(metadata.Maintainers))
+		for _, m := range metadata.Maintainers {
+			if m == nil {
+				copied.Maintainers = append(copied.Maintainers, nil)
+				continue
+			}
+			maintainer := *m
+			copied.Maintainers = append(copied.Maintainers, &maintainer)
+		}
+	}
+	return copied
+}
@@ -0,0 +1,92 @@
+package memoryrepo
+
+import (
+	"sort"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+// sortResults orders results by the sort keys, breaking ties by creation time and then id so that the
+// order is stable across searches. Scored results are ordered by relevance first if no key is given
+func sortResults(results []*MetadataDAO, scores map[*MetadataDAO]float64, keys []app.SortKey) {
+	if len(keys) == 0 && scores != nil {
+		keys = []app.SortKey{{Field: app.SortRelevance}}
+	}
+	keys = append(keys, app.SortKey{Field: app.SortCreated})
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		for _, key := range keys {
+			c := compare(key.Field, a, b, scores)
+			if key.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return a.Id < b.Id
+	})
+}
+
+// compare returns a negative number if a comes before b in the natural order of the field, positive if
+// it comes after and 0 if they are tied. The natural order of relevance is from most to least relevant
+func compare(field string, a *MetadataDAO, b *MetadataDAO, scores map[*MetadataDAO]float64) int {
+	switch field {
+	case app.SortRelevance:
+		return compareFloats(scores[b], scores[a])
+	case app.SortTitle:
+		return compareStrings(a.Title, b.Title)
+	case app.SortCompany:
+		return compareStrings(a.Company, b.Company)
+	case app.SortVersion:
+		return compareVersions(a, b)
+	case app.SortCreated:
+		return compareTimes(a.CreatedAt.UnixNano(), b.CreatedAt.UnixNano())
+	case app.SortUpdated:
+		return compareTimes(a.UpdatedAt.UnixNano(), b.UpdatedAt.UnixNano())
+	default:
+		return 0
+	}
+}
+
+// compareStrings orders strings case-insensitively
+func compareStrings(a string, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func compareFloats(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareTimes(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func paginate(results []*MetadataDAO, offset int, limit int) []*MetadataDAO {
+	if offset >= len(results) {
+		return results[:0]
+	}
+	results = results[offset:]
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results
+}
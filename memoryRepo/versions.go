@@ -0,0 +1,73 @@
+package memoryrepo
+
+import (
+	"errors"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"gitlab.com/erikwu09/yamlr/semver"
+	"gitlab.com/erikwu09/yamlr/utils"
+)
+
+// versionKey returns the sortable form of a version, or an empty string if it is not a semantic version.
+// Metadata without a key is left out of the VersionKey index
+func versionKey(version string) string {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return ""
+	}
+	return v.Key()
+}
+
+// queryByVersion matches metadata whose version satisfies a constraint such as ">=1.2.0 <2.0.0" or "~1.4".
+// Values that are not a valid constraint are matched exactly
+func (r *memoryRepository) queryByVersion(value string, tx *memdb.Txn) (*utils.Set, error) {
+	constraint, err := semver.ParseConstraint(value)
+	if err != nil {
+		return r.query("Version", value, tx)
+	}
+	var it memdb.ResultIterator
+	if lower := constraint.LowerBound(); lower != nil {
+		it, err = tx.LowerBound(metadataTable, "VersionKey", lower.Key())
+	} else {
+		it, err = tx.Get(metadataTable, "VersionKey")
+	}
+	if err != nil {
+		return nil, err
+	}
+	upper := constraint.UpperBound()
+	result := utils.NewSet()
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		metadata, k := obj.(*MetadataDAO)
+		if !k {
+			return nil, errors.New("something went wrong")
+		}
+		version, err := semver.Parse(metadata.Version)
+		if err != nil {
+			return nil, err
+		}
+		// the index is ordered by version so nothing past the upper bound can match
+		if upper != nil && version.Compare(*upper) > 0 {
+			break
+		}
+		if constraint.Check(version) {
+			result.Add(metadata)
+		}
+	}
+	return result, nil
+}
+
+// compareVersions orders semantic versions by precedence, followed by any other versions in string order.
+// Keys are compared byte-wise like the VersionKey index, as pre-release identifiers are ordered by ASCII
+func compareVersions(a *MetadataDAO, b *MetadataDAO) int {
+	switch {
+	case a.VersionKey != "" && b.VersionKey == "":
+		return -1
+	case a.VersionKey == "" && b.VersionKey != "":
+		return 1
+	}
+	if c := strings.Compare(a.VersionKey, b.VersionKey); c != 0 {
+		return c
+	}
+	return compareStrings(a.Version, b.Version)
+}
@@ -0,0 +1,63 @@
+package memoryrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/query"
+	"gitlab.com/erikwu09/yamlr/semver"
+)
+
+func Test_QueryByVersionRange(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	for _, version := range []string{"0.9.0", "1.2.0", "v1.4.2", "1.10.0", "2.0.0-beta.1", "2.0.0", "latest"} {
+		m := dummyMetadata("", "", "")
+		m.Version = version
+		repo.Insert(m, "")
+	}
+
+	search := func(criteria app.SearchCriteria) []string {
+		results, _, err := repo.Search(criteria)
+		assert.NoError(t, err)
+		versions := make([]string, 0, len(results))
+		for _, result := range results {
+			versions = append(versions, result.Version)
+		}
+		return versions
+	}
+	byVersion := []app.SortKey{{Field: app.SortVersion}}
+
+	assert.Equal(t, []string{"1.2.0", "v1.4.2", "1.10.0", "2.0.0-beta.1"},
+		search(app.SearchCriteria{Metadata: &models.Metadata{Version: ">=1.2.0 <2.0.0"}, Sort: byVersion}))
+	assert.Equal(t, []string{"v1.4.2"}, search(app.SearchCriteria{Metadata: &models.Metadata{Version: "~1.4"}}))
+	assert.Equal(t, []string{"latest"}, search(app.SearchCriteria{Metadata: &models.Metadata{Version: "latest"}}))
+
+	expr, err := query.Parse(`version:"<1.0.0 || >=2" OR version:latest`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0.9.0", "2.0.0", "latest"},
+		search(app.SearchCriteria{Metadata: &models.Metadata{}, Expr: expr, Sort: byVersion}))
+}
+
+func Test_SortByVersionPrecedence(t *testing.T) {
+	repo, _ := newMemoryRepository()
+	for _, version := range []string{"1.0.0-rc", "1.0.0", "1.0.0-RC", "1.0.0-alpha", "1.0.0-Beta"} {
+		m := dummyMetadata("", "", "")
+		m.Version = version
+		repo.Insert(m, "")
+	}
+	results, _, err := repo.Search(app.SearchCriteria{Metadata: &models.Metadata{Title: "dummyTitle"}, Sort: []app.SortKey{{Field: app.SortVersion}}})
+	assert.NoError(t, err)
+	versions := make([]string, 0, len(results))
+	for _, result := range results {
+		versions = append(versions, result.Version)
+	}
+	// pre-release identifiers are compared in ASCII order, so upper case comes first
+	assert.Equal(t, []string{"1.0.0-Beta", "1.0.0-RC", "1.0.0-alpha", "1.0.0-rc", "1.0.0"}, versions)
+	for i := 1; i < len(versions); i++ {
+		previous, _ := semver.Parse(versions[i-1])
+		current, _ := semver.Parse(versions[i])
+		assert.True(t, previous.Compare(current) < 0, versions[i])
+	}
+}
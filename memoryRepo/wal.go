@@ -0,0 +1,244 @@
+package memoryrepo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+const walInsert string = "insert"
+const walUpdate string = "update"
+const walDelete string = "delete"
+
+const walFileName string = "wal.log"
+const snapshotFileName string = "snapshot.json"
+
+// DefaultCompactEvery is the default number of log entries written between two snapshots
+const DefaultCompactEvery int = 1000
+
+// walEntry is a single write recorded in the write-ahead log
+type walEntry struct {
+	//Sequence numbers the entries of the log, entries of a snapshot have none
+	Sequence  int64 `json:",omitempty"`
+	Op        string
+	Id        string
+	Timestamp time.Time
+	Author    string           `json:",omitempty"`
+	Metadata  *models.Metadata `json:",omitempty"`
+}
+
+// writeAheadLog journals every write of a memoryRepository to disk and periodically
+// compacts the journal into a snapshot of the whole repository
+type writeAheadLog struct {
+	dir          string
+	file         *os.File
+	writer       *bufio.Writer
+	compactEvery int
+	//entries is the number of entries appended since the last snapshot
+	entries int
+	//compactAt is the number of entries at which the next compaction is attempted
+	compactAt int
+	//sequence is the sequence number of the last entry appended to the log or covered by the snapshot
+	sequence int64
+	//offset is the size of the log up to the end of the last completely written entry
+	offset int64
+}
+
+// snapshot is the content of the snapshot file, Sequence being the sequence number of the last log entry
+// it covers
+type snapshot struct {
+	Sequence int64
+	Entries  []walEntry
+}
+
+func openWriteAheadLog(dir string, compactEvery int) (*writeAheadLog, error) {
+	if compactEvery <= 0 {
+		compactEvery = DefaultCompactEvery
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &writeAheadLog{dir: dir, file: file, writer: bufio.NewWriter(file), compactEvery: compactEvery, compactAt: compactEvery}, nil
+}
+
+// replay applies the snapshot followed by every entry of the log.
+// A partially written trailing entry (i.e. the process died mid-write) is discarded, any other entry that
+// cannot be read fails the replay so that the entries written after it are not lost
+func (w *writeAheadLog) replay(apply func(walEntry) error) error {
+	snapshot, err := w.readSnapshot()
+	if err != nil {
+		return err
+	}
+	w.sequence = snapshot.Sequence
+	for _, entry := range snapshot.Entries {
+		if err := apply(entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(w.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// the last line has no trailing newline unless it was completely written
+			break
+		}
+		if err != nil {
+			return err
+		}
+		entry := walEntry{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("corrupt entry at offset %d of %s: %v", offset, filepath.Join(w.dir, walFileName), err)
+		}
+		offset += int64(len(line))
+		// entries already covered by the snapshot are left over by a compaction interrupted before the log
+		// was emptied
+		if entry.Sequence != 0 && entry.Sequence <= w.sequence {
+			continue
+		}
+		if err := apply(entry); err != nil {
+			return err
+		}
+		if entry.Sequence > w.sequence {
+			w.sequence = entry.Sequence
+		}
+		w.entries++
+	}
+	// drop the torn entry, if any, and continue appending from there
+	return w.truncate(offset)
+}
+
+// truncate cuts the log at the given offset and continues appending from there
+func (w *writeAheadLog) truncate(offset int64) error {
+	if err := w.file.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	w.writer.Reset(w.file)
+	w.offset = offset
+	return nil
+}
+
+func (w *writeAheadLog) readSnapshot() (snapshot, error) {
+	content, err := ioutil.ReadFile(filepath.Join(w.dir, snapshotFileName))
+	if os.IsNotExist(err) {
+		return snapshot{}, nil
+	}
+	if err != nil {
+		return snapshot{}, err
+	}
+	result := snapshot{}
+	if len(content) > 0 && content[0] == '[' {
+		// snapshots written before the log was sequenced are a bare list of entries
+		err = json.Unmarshal(content, &result.Entries)
+	} else {
+		err = json.Unmarshal(content, &result)
+	}
+	return result, err
+}
+
+// append durably writes an entry to the end of the log.
+// An entry that fails to be written is cut off the log so that the following entries are not appended
+// after a torn one
+func (w *writeAheadLog) append(entry walEntry) error {
+	entry.Sequence = w.sequence + 1
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if err := w.write(line); err != nil {
+		if truncateErr := w.truncate(w.offset); truncateErr != nil {
+			return fmt.Errorf("%v, cutting off the torn entry failed: %v", err, truncateErr)
+		}
+		return err
+	}
+	w.sequence = entry.Sequence
+	w.offset += int64(len(line))
+	w.entries++
+	return nil
+}
+
+func (w *writeAheadLog) write(line []byte) error {
+	if _, err := w.writer.Write(line); err != nil {
+		return err
+	}
+	if err := w.writer.Flush(); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+func (w *writeAheadLog) shouldCompact() bool {
+	return w.entries >= w.compactAt
+}
+
+// postponeCompaction backs off after a failed compaction, which is attempted again once another
+// compactEvery entries have been appended
+func (w *writeAheadLog) postponeCompaction() {
+	w.compactAt = w.entries + w.compactEvery
+}
+
+// compact replaces the current snapshot with the given one and empties the log.
+// The snapshot records the sequence number of the last entry appended so that the entries left in the log
+// by a crash before it is emptied are not replayed twice
+func (w *writeAheadLog) compact(entries []walEntry) error {
+	content, err := json.Marshal(snapshot{Sequence: w.sequence, Entries: entries})
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(w.dir, snapshotFileName+".tmp")
+	if err := writeFileSync(tmp, content); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(w.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	if err := w.truncate(0); err != nil {
+		return err
+	}
+	w.entries = 0
+	w.compactAt = w.compactEvery
+	return nil
+}
+
+func (w *writeAheadLog) close() error {
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+func writeFileSync(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,291 @@
+package memoryrepo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var discardLogger = log.New(ioutil.Discard, "", 0)
+
+// tearingWriter writes the first limit bytes it is given and fails, like a full disk
+type tearingWriter struct {
+	writer io.Writer
+	limit  int
+}
+
+func (w tearingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		p = p[:w.limit]
+	}
+	n, err := w.writer.Write(p)
+	if err == nil {
+		err = errors.New("no space left on device")
+	}
+	return n, err
+}
+
+func Test_DurableRepositoryReplaysLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	id, err := repo.Insert(dummyMetadata("", "", "microsoft.com"), "")
+	assert.NoError(t, err)
+	updated := dummyMetadata("updated", "", "microsoft.com")
+	_, err = repo.Update(id, updated, 0, "")
+	assert.NoError(t, err)
+	deleted, err := repo.Insert(dummyMetadata("deleted", "", "microsoft.com"), "")
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Delete(deleted, 0, ""))
+	assert.NoError(t, repo.Close())
+
+	restarted, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer restarted.Close()
+	result, err := restarted.Get(id)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+	_, err = restarted.Get(deleted)
+	assert.Error(t, err)
+	revisions, err := restarted.GetRevisions(id)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 2)
+
+	tx := restarted.memoryDb.Txn(false)
+	defer tx.Abort()
+	results, err := restarted.queryByEmails([]string{"email@microsoft.com"}, tx)
+	assert.NoError(t, err)
+	assert.True(t, len(results.ToSlice()) == 1)
+}
+
+func Test_DurableRepositoryCompactsIntoSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := newDurableMemoryRepository(dir, 2, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	id1, _ := repo.Insert(dummyMetadata("1", "", ""), "")
+	_, err = repo.Update(id1, dummyMetadata("1updated", "", ""), 0, "jane")
+	assert.NoError(t, err)
+	id2, _ := repo.Insert(dummyMetadata("2", "", ""), "")
+	id3, _ := repo.Insert(dummyMetadata("3", "", ""), "")
+	deleted, _ := repo.Insert(dummyMetadata("deleted", "", ""), "")
+	assert.NoError(t, repo.Delete(deleted, 0, "ci"))
+	assert.NoError(t, repo.Close())
+
+	_, err = os.Stat(filepath.Join(dir, snapshotFileName))
+	assert.NoError(t, err)
+
+	restarted, err := newDurableMemoryRepository(dir, 2, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer restarted.Close()
+	for _, id := range []uuid.UUID{id1, id2, id3} {
+		_, err := restarted.Get(id)
+		assert.NoError(t, err)
+	}
+	revisions, err := restarted.GetRevisions(id1)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 2)
+	assert.Equal(t, "dummyTitle1updated", revisions[1].Metadata.Title)
+	assert.Equal(t, "jane", revisions[1].Author)
+	// the history of deleted metadata is part of the snapshot
+	_, err = restarted.Get(deleted)
+	assert.Error(t, err)
+	revisions, err = restarted.GetRevisions(deleted)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 2)
+	assert.True(t, revisions[1].Deleted)
+	assert.Equal(t, "ci", revisions[1].Author)
+}
+
+func Test_DurableRepositoryDiscardsTornEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	id, _ := repo.Insert(dummyMetadata("", "", ""), "")
+	assert.NoError(t, repo.Close())
+
+	file, _ := os.OpenFile(filepath.Join(dir, walFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	file.Write([]byte(`{"Op":"insert","Id":`))
+	file.Close()
+
+	restarted, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer restarted.Close()
+	_, err = restarted.Get(id)
+	assert.NoError(t, err)
+}
+
+func Test_DurableRepositoryRejectsCorruptEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	repo.Insert(dummyMetadata("", "", ""), "")
+	assert.NoError(t, repo.Close())
+
+	// a complete entry that cannot be read is followed by committed writes, which must not be discarded
+	file, _ := os.OpenFile(filepath.Join(dir, walFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	file.Write([]byte("{\"Op\":\"insert\",\"Id\":\n"))
+	file.Close()
+	repo, err = newDurableMemoryRepository(dir, 100, discardLogger)
+	assert.Error(t, err)
+	assert.Nil(t, repo)
+}
+
+func Test_DurableRepositoryRetriesFailedCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the snapshot cannot be written while a directory stands in its way
+	blocker := filepath.Join(dir, snapshotFileName+".tmp")
+	assert.NoError(t, os.MkdirAll(blocker, 0755))
+	output := &bytes.Buffer{}
+	repo, err := newDurableMemoryRepository(dir, 2, log.New(output, "", 0))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer repo.Close()
+	_, err = repo.Insert(dummyMetadata("1", "", ""), "")
+	assert.NoError(t, err)
+	_, err = repo.Insert(dummyMetadata("2", "", ""), "")
+	assert.NoError(t, err)
+	assert.Contains(t, output.String(), "compacting the write-ahead log failed, retrying in 2 writes")
+
+	// the compaction is not attempted again on the next write
+	assert.NoError(t, os.Remove(blocker))
+	_, err = repo.Insert(dummyMetadata("3", "", ""), "")
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, snapshotFileName))
+	assert.True(t, os.IsNotExist(err))
+	_, err = repo.Insert(dummyMetadata("4", "", ""), "")
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, snapshotFileName))
+	assert.NoError(t, err)
+}
+
+func Test_DurableRepositorySkipsEntriesCoveredBySnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	id, _ := repo.Insert(dummyMetadata("", "", ""), "")
+	_, err = repo.Update(id, dummyMetadata("updated", "", ""), 0, "")
+	assert.NoError(t, err)
+	deleted, _ := repo.Insert(dummyMetadata("deleted", "", ""), "")
+	assert.NoError(t, repo.Delete(deleted, 0, ""))
+	journal, err := ioutil.ReadFile(filepath.Join(dir, walFileName))
+	assert.NoError(t, err)
+	tx := repo.memoryDb.Txn(false)
+	snapshot, err := repo.dump(tx)
+	tx.Abort()
+	assert.NoError(t, err)
+	assert.NoError(t, repo.wal.compact(snapshot))
+	assert.NoError(t, repo.Close())
+	// the process died after the snapshot was written but before the log was emptied
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, walFileName), journal, 0644))
+
+	restarted, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	revisions, err := restarted.GetRevisions(id)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 2)
+	revisions, err = restarted.GetRevisions(deleted)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 2)
+
+	// writes after the restart continue the sequence of the snapshot
+	_, err = restarted.Update(id, dummyMetadata("updated again", "", ""), 0, "")
+	assert.NoError(t, err)
+	assert.NoError(t, restarted.Close())
+	restarted, err = newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer restarted.Close()
+	revisions, err = restarted.GetRevisions(id)
+	assert.NoError(t, err)
+	assert.True(t, len(revisions) == 3)
+}
+
+func Test_DurableRepositoryCutsOffFailedAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	id1, _ := repo.Insert(dummyMetadata("1", "", ""), "")
+	repo.wal.writer = bufio.NewWriter(tearingWriter{writer: repo.wal.file, limit: 20})
+	_, err = repo.Insert(dummyMetadata("failed", "", ""), "")
+	assert.EqualError(t, err, "no space left on device")
+	// the next write is not appended after the torn entry
+	id2, err := repo.Insert(dummyMetadata("2", "", ""), "")
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Close())
+
+	restarted, err := newDurableMemoryRepository(dir, 100, discardLogger)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer restarted.Close()
+	for _, id := range []uuid.UUID{id1, id2} {
+		_, err := restarted.Get(id)
+		assert.NoError(t, err)
+	}
+}
@@ -1,29 +1,153 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type SearchResults struct {
-	Results []Metadata `yaml:"results,flow"`
+	Results []Metadata `yaml:"results,flow" json:"results"`
+	// Total is the number of matches across all pages
+	Total int `yaml:"total" json:"total"`
+	// Next is the cursor of the next page, empty on the last page
+	Next string `yaml:"next,omitempty" json:"next,omitempty"`
+}
+
+// FacetValue is the number of metadata sharing a value of a field
+type FacetValue struct {
+	Value string `yaml:"value" json:"value"`
+	Count int    `yaml:"count" json:"count"`
+}
+
+// FacetResults holds the values of each aggregated field, ordered by descending count
+type FacetResults struct {
+	// Total is the number of metadata that were aggregated
+	Total  int                     `yaml:"total" json:"total"`
+	Facets map[string][]FacetValue `yaml:"facets" json:"facets"`
+}
+
+// SearchRequest is the body of a search: every field set in the metadata must match, and so must the
+// optional boolean query expression
+type SearchRequest struct {
+	Metadata `yaml:",inline"`
+	Query    string `yaml:"query" json:"query"`
+	// QueryParameter is a query expression passed outside of the body, which must match as well
+	QueryParameter string `yaml:"-" json:"-"`
+	// Match selects how each field of the document is matched, keyed by field name
+	Match map[string]FieldMatch `yaml:"match" json:"match"`
+	// Sort is a comma separated list of relevance, title, company, version, created and updated,
+	// each optionally prefixed with - for descending order
+	Sort   string `yaml:"sort" json:"sort"`
+	Limit  int    `yaml:"limit" json:"limit"`
+	Offset int    `yaml:"offset" json:"offset"`
+	// Cursor is the next cursor of a previous page; it replaces Offset
+	Cursor string `yaml:"cursor" json:"cursor"`
+}
+
+// Match modes of a FieldMatch
+const (
+	MatchExact  = "exact"
+	MatchPrefix = "prefix"
+	MatchGlob   = "glob"
+	MatchFuzzy  = "fuzzy"
+)
+
+// DefaultMaxDistance is the default number of typos tolerated by fuzzy matching
+const DefaultMaxDistance = 2
+
+// FieldMatch selects how a field of a search document is matched: exactly (the default), by prefix,
+// by a glob pattern where * matches any run of characters and ? matches a single character, or fuzzily
+// within MaxDistance edits
+type FieldMatch struct {
+	Mode        string `yaml:"mode" json:"mode"`
+	IgnoreCase  bool   `yaml:"ignoreCase" json:"ignoreCase"`
+	MaxDistance int    `yaml:"maxDistance" json:"maxDistance"`
+}
+
+// UnmarshalYAML also accepts the shorthands exact, ignorecase, prefix, glob and fuzzy
+func (m *FieldMatch) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var shorthand string
+	if err := unmarshal(&shorthand); err == nil {
+		*m = fieldMatchShorthand(shorthand)
+		return nil
+	}
+	type plain FieldMatch
+	return unmarshal((*plain)(m))
+}
+
+// UnmarshalJSON accepts the same shorthands as UnmarshalYAML
+func (m *FieldMatch) UnmarshalJSON(data []byte) error {
+	var shorthand string
+	if err := json.Unmarshal(data, &shorthand); err == nil {
+		*m = fieldMatchShorthand(shorthand)
+		return nil
+	}
+	type plain FieldMatch
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+func fieldMatchShorthand(shorthand string) FieldMatch {
+	if shorthand == "ignorecase" {
+		return FieldMatch{Mode: MatchExact, IgnoreCase: true}
+	}
+	return FieldMatch{Mode: shorthand}
 }
 
 type Metadata struct {
-	Title       string        `yaml:"title"`
-	Version     string        `yaml:"version"`
-	Company     string        `yaml:"company"`
-	Website     string        `yaml:"website"`
-	Source      string        `yaml:"source"`
-	License     string        `yaml:"license"`
-	Description string        `yaml:"description"`
-	Maintainers []*Maintainer `yaml:"maintainers,flow"`
+	Title       string        `yaml:"title" json:"title"`
+	Version     string        `yaml:"version" json:"version"`
+	Company     string        `yaml:"company" json:"company"`
+	Website     string        `yaml:"website" json:"website"`
+	Source      string        `yaml:"source" json:"source"`
+	License     string        `yaml:"license" json:"license"`
+	Description string        `yaml:"description" json:"description"`
+	Maintainers []*Maintainer `yaml:"maintainers,flow" json:"maintainers"`
 }
 
 type Maintainer struct {
-	Name  string `yaml:"name"`
-	Email string `yaml:"email"`
+	Name  string `yaml:"name" json:"name"`
+	Email string `yaml:"email" json:"email"`
+}
+
+// Revision is an immutable copy of a metadata as it was after a create or update, or a tombstone
+// recording its deletion
+type Revision struct {
+	Number    int       `yaml:"revision" json:"revision"`
+	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
+	// Author is the subject of the principal who made the change, anonymous when authentication is disabled
+	Author string `yaml:"author,omitempty" json:"author,omitempty"`
+	// Deleted marks the tombstone of a deleted metadata, which has no metadata
+	Deleted  bool     `yaml:"deleted,omitempty" json:"deleted,omitempty"`
+	Metadata Metadata `yaml:"metadata" json:"metadata"`
+}
+
+type Revisions struct {
+	Revisions []Revision `yaml:"revisions" json:"revisions"`
 }
 
 type Response struct {
-	Id string `yaml:"id"`
+	Id string `yaml:"id" json:"id"`
+	// Warnings lists the advisory validation findings of a create or update
+	Warnings []Warning `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+}
+
+// ValidationResult is the result of a dry-run validation
+type ValidationResult struct {
+	// Metadata is the sanitized metadata
+	Metadata Metadata  `yaml:"metadata" json:"metadata"`
+	Warnings []Warning `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+}
+
+// Warning is an advisory validation finding that did not prevent a write
+type Warning struct {
+	Path    string `yaml:"path" json:"path"`
+	Reason  string `yaml:"reason" json:"reason"`
+	Rule    string `yaml:"rule,omitempty" json:"rule,omitempty"`
+	Line    int    `yaml:"line,omitempty" json:"line,omitempty"`
+	Column  int    `yaml:"column,omitempty" json:"column,omitempty"`
+	Message string `yaml:"message" json:"message"`
 }
 
 type ErrorResponse struct {
-	Message string `yaml:"message"`
+	Message string `yaml:"message" json:"message"`
 }
@@ -0,0 +1,60 @@
+package query
+
+import "fmt"
+
+// Expr is a node of a parsed boolean search expression
+type Expr interface {
+	String() string
+}
+
+// And matches metadata matched by both sides
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+// Or matches metadata matched by either side
+type Or struct {
+	Left  Expr
+	Right Expr
+}
+
+// Not matches metadata not matched by the inner expression
+type Not struct {
+	Expr Expr
+}
+
+// Term matches metadata whose field matches the value. Field is the canonical (index) name of the field
+type Term struct {
+	Field    string
+	Value    string
+	Position int
+}
+
+func (e And) String() string {
+	return fmt.Sprintf("(%s AND %s)", e.Left, e.Right)
+}
+
+func (e Or) String() string {
+	return fmt.Sprintf("(%s OR %s)", e.Left, e.Right)
+}
+
+func (e Not) String() string {
+	return fmt.Sprintf("NOT %s", e.Expr)
+}
+
+func (e Term) String() string {
+	return fmt.Sprintf("%s:%q", e.Field, e.Value)
+}
+
+// Fields maps the field names accepted in expressions to the canonical names used by the repository
+var Fields = map[string]string{
+	"title":       "Title",
+	"company":     "Company",
+	"website":     "Website",
+	"source":      "Source",
+	"license":     "License",
+	"version":     "Version",
+	"description": "Description",
+	"email":       "Email",
+}
@@ -0,0 +1,223 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError is returned when an expression cannot be parsed. Position is the 1-based
+// character offset of the offending token
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenColon
+	tokenLeftParen
+	tokenRightParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return fmt.Sprintf("%q", t.value)
+	default:
+		return fmt.Sprintf("'%s'", t.value)
+	}
+}
+
+// Parse parses a boolean search expression such as
+//
+//	company:Microsoft AND (license:MIT OR license:"Apache-2.0") AND NOT title:Cortana
+//
+// AND binds tighter than OR and NOT binds tightest; operators are case-insensitive
+func Parse(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, SyntaxError{Position: next.position, Message: fmt.Sprintf("unexpected %s", next.describe())}
+	}
+	return expr, nil
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		position := i + 1
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == ':':
+			tokens = append(tokens, token{kind: tokenColon, value: ":", position: position})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, value: "(", position: position})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, value: ")", position: position})
+			i++
+		case c == '"':
+			sb := strings.Builder{}
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, SyntaxError{Position: position, Message: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), position: position})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`:()"`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := tokenWord
+			switch strings.ToUpper(word) {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "NOT":
+				kind = tokenNot
+			}
+			tokens = append(tokens, token{kind: kind, value: word, position: position})
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, position: len(runes) + 1})
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLeftParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, SyntaxError{Position: closing.position, Message: fmt.Sprintf("expected ')' but found %s", closing.describe())}
+		}
+		return expr, nil
+	case tokenWord:
+		field, k := Fields[strings.ToLower(t.value)]
+		if !k {
+			return nil, SyntaxError{Position: t.position, Message: fmt.Sprintf("unknown field '%s'", t.value)}
+		}
+		if colon := p.next(); colon.kind != tokenColon {
+			return nil, SyntaxError{Position: colon.position, Message: fmt.Sprintf("expected ':' but found %s", colon.describe())}
+		}
+		value := p.next()
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, SyntaxError{Position: value.position, Message: fmt.Sprintf("expected a value but found %s", value.describe())}
+		}
+		return Term{Field: field, Value: value.value, Position: t.position}, nil
+	default:
+		return nil, SyntaxError{Position: t.position, Message: fmt.Sprintf("expected a field or '(' but found %s", t.describe())}
+	}
+}
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParsesPrecedence(t *testing.T) {
+	expr, err := Parse(`company:Microsoft AND (license:MIT OR license:"Apache-2.0") AND NOT title:Cortana`)
+	assert.NoError(t, err)
+	assert.Equal(t, `((Company:"Microsoft" AND (License:"MIT" OR License:"Apache-2.0")) AND NOT Title:"Cortana")`, expr.String())
+
+	expr, err = Parse(`title:a or title:b and not title:c`)
+	assert.NoError(t, err)
+	assert.Equal(t, `(Title:"a" OR (Title:"b" AND NOT Title:"c"))`, expr.String())
+
+	expr, err = Parse(`description:"managed \"hadoop\""`)
+	assert.NoError(t, err)
+	assert.Equal(t, Term{Field: "Description", Value: `managed "hadoop"`, Position: 1}, expr)
+}
+
+func Test_ReportsErrorPosition(t *testing.T) {
+	cases := map[string]int{
+		`company:Microsoft AND`:          22,
+		`company:Microsoft AND (title:a`: 31,
+		`colour:red`:                     1,
+		`title Cortana`:                  7,
+		`title:"Cortana`:                 7,
+		`title:a title:b`:                9,
+		`title:a AND OR title:b`:         13,
+	}
+	for input, position := range cases {
+		_, err := Parse(input)
+		syntaxErr, k := err.(SyntaxError)
+		assert.True(t, k, input)
+		assert.Equal(t, position, syntaxErr.Position, input)
+	}
+}
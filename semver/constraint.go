@@ -0,0 +1,246 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a set of version ranges, e.g. ">=1.2.0 <2.0.0 || ~3.1". A version satisfies the
+// constraint if it is within any of its ranges
+type Constraint struct {
+	ranges []versionRange
+}
+
+// versionRange is an interval of versions; a nil bound means unbounded
+type versionRange struct {
+	lower          *Version
+	lowerInclusive bool
+	upper          *Version
+	upperInclusive bool
+}
+
+// ParseConstraint parses a constraint made of ranges separated by ||. Each range is a space
+// separated list of comparisons that must all hold:
+//
+//	=1.2.3 or 1.2.3   exactly 1.2.3 (1.2 and 1.2.x mean >=1.2.0 <1.3.0)
+//	>1.2.3 >=1.2.3 <1.2.3 <=1.2.3
+//	~1.2.3            >=1.2.3 <1.3.0
+//	^1.2.3            >=1.2.3 <2.0.0 (^0.2.3 means >=0.2.3 <0.3.0)
+//	1.2.0 - 1.4.0     >=1.2.0 <=1.4.0
+func ParseConstraint(constraint string) (Constraint, error) {
+	c := Constraint{}
+	for _, part := range strings.Split(constraint, "||") {
+		r, err := parseRange(part)
+		if err != nil {
+			return c, err
+		}
+		c.ranges = append(c.ranges, r)
+	}
+	return c, nil
+}
+
+func parseRange(s string) (versionRange, error) {
+	r := versionRange{}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return r, fmt.Errorf("empty version range")
+	}
+	if len(fields) == 3 && fields[1] == "-" {
+		lower, err := Parse(fields[0])
+		if err != nil {
+			return r, err
+		}
+		upper, err := Parse(fields[2])
+		if err != nil {
+			return r, err
+		}
+		r.restrictLower(lower, true)
+		r.restrictUpper(upper, true)
+		return r, nil
+	}
+	for _, field := range fields {
+		if err := r.apply(field); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// apply narrows the range with a single comparison
+func (r *versionRange) apply(comparison string) error {
+	operator := ""
+	for _, op := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(comparison, op) {
+			operator = op
+			break
+		}
+	}
+	value := strings.TrimSpace(strings.TrimPrefix(comparison, operator))
+	version, precision, err := parsePartial(value)
+	if err != nil {
+		return err
+	}
+	switch operator {
+	case ">=":
+		r.restrictLower(version, true)
+	case ">":
+		if precision < 3 {
+			// >1.2 means >=1.3.0
+			r.restrictLower(bump(version, precision), true)
+		} else {
+			r.restrictLower(version, false)
+		}
+	case "<":
+		r.restrictUpper(version, false)
+	case "<=":
+		if precision < 3 {
+			r.restrictUpper(bump(version, precision), false)
+		} else {
+			r.restrictUpper(version, true)
+		}
+	case "~":
+		r.restrictLower(version, true)
+		if precision == 1 {
+			r.restrictUpper(bump(version, 1), false)
+		} else {
+			r.restrictUpper(bump(version, 2), false)
+		}
+	case "^":
+		r.restrictLower(version, true)
+		switch {
+		case version.Major > 0 || precision == 1:
+			r.restrictUpper(bump(version, 1), false)
+		case version.Minor > 0 || precision == 2:
+			r.restrictUpper(bump(version, 2), false)
+		default:
+			r.restrictUpper(bump(version, 3), false)
+		}
+	default:
+		if precision == 0 {
+			// * matches every version
+			return nil
+		}
+		r.restrictLower(version, true)
+		if precision < 3 {
+			r.restrictUpper(bump(version, precision), false)
+		} else {
+			r.restrictUpper(version, true)
+		}
+	}
+	return nil
+}
+
+// parsePartial parses a possibly partial version like 1, 1.2, 1.2.x or * and returns how many of
+// the major, minor and patch numbers were given
+func parsePartial(value string) (Version, int, error) {
+	value = strings.TrimPrefix(value, "v")
+	core := value
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	precision := 0
+	for _, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		precision++
+	}
+	if precision == len(parts) {
+		v, err := Parse(value)
+		if err != nil {
+			return v, 0, err
+		}
+		return v, precision, nil
+	}
+	for _, part := range parts[precision:] {
+		if part != "x" && part != "X" && part != "*" {
+			return Version{}, 0, fmt.Errorf("invalid version %s", value)
+		}
+	}
+	if precision == 0 {
+		return Version{}, 0, nil
+	}
+	v, err := Parse(strings.Join(parts[:precision], "."))
+	return v, precision, err
+}
+
+// bump returns the smallest version greater than every version sharing the first precision numbers
+func bump(v Version, precision int) Version {
+	switch precision {
+	case 1:
+		return Version{Major: v.Major + 1}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+func (r *versionRange) restrictLower(v Version, inclusive bool) {
+	if r.lower == nil || v.Compare(*r.lower) > 0 || (v.Compare(*r.lower) == 0 && !inclusive) {
+		r.lower = &v
+		r.lowerInclusive = inclusive
+	}
+}
+
+func (r *versionRange) restrictUpper(v Version, inclusive bool) {
+	if r.upper == nil || v.Compare(*r.upper) < 0 || (v.Compare(*r.upper) == 0 && !inclusive) {
+		r.upper = &v
+		r.upperInclusive = inclusive
+	}
+}
+
+func (r versionRange) contains(v Version) bool {
+	if r.lower != nil {
+		c := v.Compare(*r.lower)
+		if c < 0 || (c == 0 && !r.lowerInclusive) {
+			return false
+		}
+	}
+	if r.upper != nil {
+		c := v.Compare(*r.upper)
+		if c > 0 || (c == 0 && !r.upperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns whether the version satisfies the constraint
+func (c Constraint) Check(v Version) bool {
+	for _, r := range c.ranges {
+		if r.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// LowerBound returns the smallest lower bound of the constraint, or nil if it is unbounded
+func (c Constraint) LowerBound() *Version {
+	var lowest *Version
+	for _, r := range c.ranges {
+		if r.lower == nil {
+			return nil
+		}
+		if lowest == nil || r.lower.Compare(*lowest) < 0 {
+			lowest = r.lower
+		}
+	}
+	return lowest
+}
+
+// UpperBound returns the largest upper bound of the constraint, or nil if it is unbounded
+func (c Constraint) UpperBound() *Version {
+	var highest *Version
+	for _, r := range c.ranges {
+		if r.upper == nil {
+			return nil
+		}
+		if highest == nil || r.upper.Compare(*highest) > 0 {
+			highest = r.upper
+		}
+	}
+	return highest
+}
@@ -0,0 +1,50 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConstraintCheck(t *testing.T) {
+	cases := map[string]map[string]bool{
+		">=1.2.0 <2.0.0": {"1.1.9": false, "1.2.0": true, "1.9.9": true, "2.0.0-rc.1": true, "2.0.0": false},
+		"~1.4":           {"1.3.9": false, "1.4.0": true, "1.4.7": true, "1.5.0": false},
+		"~1.4.2":         {"1.4.1": false, "1.4.2": true, "1.5.0": false},
+		"^1.4.2":         {"1.4.1": false, "1.9.0": true, "2.0.0": false},
+		"^0.2.3":         {"0.2.3": true, "0.2.9": true, "0.3.0": false},
+		"1.2.x":          {"1.1.0": false, "1.2.0": true, "1.2.5": true, "1.3.0": false},
+		"1.2.3":          {"1.2.3": true, "v1.2.3": true, "1.2.4": false},
+		">1.2":           {"1.2.9": false, "1.3.0": true},
+		"<=1.2":          {"1.2.9": true, "1.3.0": false},
+		"1.0.0 - 1.2.0":  {"0.9.0": false, "1.0.0": true, "1.2.0": true, "1.2.1": false},
+		"<1.0.0 || >=3":  {"0.1.0": true, "1.0.0": false, "3.1.0": true},
+		"*":              {"0.0.1": true, "9.9.9": true},
+	}
+	for constraint, versions := range cases {
+		c, err := ParseConstraint(constraint)
+		assert.NoError(t, err, constraint)
+		for version, expected := range versions {
+			v, err := Parse(version)
+			assert.NoError(t, err, version)
+			assert.Equal(t, expected, c.Check(v), "%s %s", constraint, version)
+		}
+	}
+}
+
+func Test_ConstraintBounds(t *testing.T) {
+	c, err := ParseConstraint(">=1.2.0 <2.0.0 || ~3.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", c.LowerBound().String())
+	assert.Equal(t, "3.2.0", c.UpperBound().String())
+
+	c, err = ParseConstraint(">=1.2.0 || <1.0.0")
+	assert.NoError(t, err)
+	assert.Nil(t, c.LowerBound())
+	assert.Nil(t, c.UpperBound())
+
+	for _, invalid := range []string{"", ">=", "~1.x.3", "latest", ">=1.0.0 ||"} {
+		_, err := ParseConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,198 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version as defined by https://semver.org/spec/v2.0.0.html
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease []string
+	Build      []string
+}
+
+// Parse parses a version leniently: a leading v and missing minor or patch numbers are accepted,
+// so "v1.2" is parsed as 1.2.0. Anything else has to follow the SemVer 2.0 grammar
+func Parse(version string) (Version, error) {
+	version = strings.TrimSpace(version)
+	if strings.HasPrefix(version, "v") || strings.HasPrefix(version, "V") {
+		version = version[1:]
+	}
+	v := Version{}
+	if version == "" {
+		return v, fmt.Errorf("empty version")
+	}
+	if i := strings.Index(version, "+"); i >= 0 {
+		build := version[i+1:]
+		version = version[:i]
+		identifiers, err := parseIdentifiers(build, false)
+		if err != nil {
+			return v, fmt.Errorf("build metadata %s", err)
+		}
+		v.Build = identifiers
+	}
+	if i := strings.Index(version, "-"); i >= 0 {
+		preRelease := version[i+1:]
+		version = version[:i]
+		identifiers, err := parseIdentifiers(preRelease, true)
+		if err != nil {
+			return v, fmt.Errorf("pre-release %s", err)
+		}
+		v.PreRelease = identifiers
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("%s has more than major, minor and patch versions", version)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		n, err := parseNumber(part)
+		if err != nil {
+			return v, fmt.Errorf("%s version %s", []string{"major", "minor", "patch"}[i], err)
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+	return v, nil
+}
+
+func parseNumber(part string) (uint64, error) {
+	if part == "" {
+		return 0, fmt.Errorf("is empty")
+	}
+	for _, c := range part {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%s is not a number", part)
+		}
+	}
+	if len(part) > 1 && part[0] == '0' {
+		return 0, fmt.Errorf("%s has a leading zero", part)
+	}
+	n, err := strconv.ParseUint(part, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s is too large", part)
+	}
+	return n, nil
+}
+
+// parseIdentifiers parses dot separated identifiers made of [0-9A-Za-z-]. Numeric pre-release
+// identifiers must not have leading zeros
+func parseIdentifiers(s string, preRelease bool) ([]string, error) {
+	identifiers := strings.Split(s, ".")
+	for _, identifier := range identifiers {
+		if identifier == "" {
+			return nil, fmt.Errorf("has an empty identifier")
+		}
+		numeric := true
+		for _, c := range identifier {
+			switch {
+			case c >= '0' && c <= '9':
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-':
+				numeric = false
+			default:
+				return nil, fmt.Errorf("identifier %s contains %q, only [0-9A-Za-z-] are allowed", identifier, c)
+			}
+		}
+		if preRelease && numeric && len(identifier) > 1 && identifier[0] == '0' {
+			return nil, fmt.Errorf("identifier %s has a leading zero", identifier)
+		}
+	}
+	return identifiers, nil
+}
+
+// String returns the canonical form of the version
+func (v Version) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch))
+	if len(v.PreRelease) > 0 {
+		sb.WriteString("-" + strings.Join(v.PreRelease, "."))
+	}
+	if len(v.Build) > 0 {
+		sb.WriteString("+" + strings.Join(v.Build, "."))
+	}
+	return sb.String()
+}
+
+// Compare returns -1, 0 or 1 depending on the precedence of v relative to other. Build metadata is ignored
+func (v Version) Compare(other Version) int {
+	if c := compareNumbers(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareNumbers(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareNumbers(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	// a release has a higher precedence than its pre-releases
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		if c := compareIdentifiers(v.PreRelease[i], other.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareNumbers(uint64(len(v.PreRelease)), uint64(len(other.PreRelease)))
+}
+
+func compareNumbers(a uint64, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// numeric identifiers are compared numerically and have a lower precedence than alphanumeric ones
+func compareIdentifiers(a string, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareNumbers(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Key encodes the version into a string whose byte order matches version precedence, so that
+// versions can be stored in an ordered index
+func (v Version) Key() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%020d.%020d.%020d", v.Major, v.Minor, v.Patch))
+	if len(v.PreRelease) == 0 {
+		// sorts after any pre-release
+		sb.WriteString("~")
+		return sb.String()
+	}
+	sb.WriteString("-")
+	for i, identifier := range v.PreRelease {
+		if i > 0 {
+			// sorts before any identifier character so that shorter lists come first
+			sb.WriteString("!")
+		}
+		if n, err := strconv.ParseUint(identifier, 10, 64); err == nil {
+			sb.WriteString(fmt.Sprintf("0%020d", n))
+		} else {
+			sb.WriteString("1" + identifier)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	v, err := Parse("v1.2.3-rc.1+build.5")
+	assert.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 3, PreRelease: []string{"rc", "1"}, Build: []string{"build", "5"}}, v)
+	assert.Equal(t, "1.2.3-rc.1+build.5", v.String())
+
+	v, err = Parse("1.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.4.0", v.String())
+
+	for _, invalid := range []string{"", "1.2.3.4", "01.2.3", "1.a.3", "1.2.3-", "1.2.3-rc..1", "1.2.3-01", "1.2.3+b_1"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_KeyOrderMatchesPrecedence(t *testing.T) {
+	// ordered by precedence, see https://semver.org/#spec-item-11
+	ordered := []string{"0.9.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.2.0", "1.10.0", "2.0.0"}
+	keys := make([]string, 0, len(ordered))
+	for i, s := range ordered {
+		v, err := Parse(s)
+		assert.NoError(t, err)
+		if i > 0 {
+			previous, _ := Parse(ordered[i-1])
+			assert.Equal(t, -1, previous.Compare(v), s)
+			assert.Equal(t, 1, v.Compare(previous), s)
+		}
+		keys = append(keys, v.Key())
+	}
+	assert.True(t, sort.StringsAreSorted(keys))
+
+	a, _ := Parse("1.0.0+build.1")
+	b, _ := Parse("1.0.0+build.2")
+	assert.Equal(t, 0, a.Compare(b))
+	assert.Equal(t, a.Key(), b.Key())
+}
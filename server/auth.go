@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+
+	"gitlab.com/erikwu09/yamlr/app"
+)
+
+// authenticate attaches the principal of the request to its context. Requests carrying invalid credentials
+// are rejected, and so are anonymous requests, reads excepted when AnonymousReads is set.
+// Every request is anonymous when no Authenticator is set
+func (s *yamlMetadataService) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if s.options.Authenticator == nil {
+			next.ServeHTTP(res, req)
+			return
+		}
+		principal, err := s.options.Authenticator.Authenticate(req)
+		if err == nil && principal == nil && (isWrite(req) || !s.options.AnonymousReads) {
+			err = app.UnauthorizedError{Reason: "missing credentials"}
+		}
+		if err != nil {
+			res.Header().Set("WWW-Authenticate", `Bearer realm="yamlr"`)
+			s.writeErrorResponse(res, req, err)
+			return
+		}
+		if principal != nil {
+			req = req.WithContext(app.ContextWithPrincipal(req.Context(), *principal))
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+// isWrite returns whether the request creates, updates or deletes metadata
+func isWrite(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return req.URL.Path == "/api/metadata"
+	}
+	return false
+}
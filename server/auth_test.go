@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/auth"
+	"gitlab.com/erikwu09/yamlr/validation"
+)
+
+func Test_Authenticate(t *testing.T) {
+	authenticator, err := auth.NewAPIKeyAuthenticator([]byte("keys:\n  - {key: 0123456789abcdef0123, subject: ci, email: ci@example.com}\n"))
+	assert.NoError(t, err)
+	s := newTestService(t, insertOnlyRepository{}, validation.SimpleValidator{})
+	s.options.Authenticator = authenticator
+
+	// the principal is available to the handlers
+	var principal app.Principal
+	s.router.HandleFunc("/api/whoami", func(res http.ResponseWriter, req *http.Request) {
+		principal, _ = app.PrincipalFromContext(req.Context())
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/whoami", nil)
+	req.Header.Set(auth.APIKeyHeader, "0123456789abcdef0123")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, app.Principal{Subject: "ci", Email: "ci@example.com", Method: auth.MethodAPIKey}, principal)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(data))
+	req.Header.Set(auth.APIKeyHeader, "0123456789abcdef0123")
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(data))
+	req.Header.Set(auth.APIKeyHeader, "0123456789abcdef0124")
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(req))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Equal(t, `Bearer realm="yamlr"`, res.Header().Get("WWW-Authenticate"))
+	assert.JSONEq(t, `{"Code":"unauthorized","ErrorMessage":"Unauthorized due to unknown API key"}`, res.Body.String())
+
+	// anonymous requests are rejected, reads excepted when anonymous reads are allowed
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(data)))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	s.options.AnonymousReads = true
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(data)))
+	assert.Equal(t, http.StatusOK, res.Code)
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(data)))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
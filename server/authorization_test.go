@@ -0,0 +1,145 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/auth"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/validation"
+	"gopkg.in/yaml.v2"
+)
+
+// maintainedRepository holds the metadata of data at revision 1 and accepts the writes of it
+type maintainedRepository struct {
+	app.MetadataRepository
+}
+
+func (maintainedRepository) Insert(metadata *models.Metadata, author string) (uuid.UUID, error) {
+	return uuid.MustParse("8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d"), nil
+}
+
+func (maintainedRepository) GetCurrentRevision(id uuid.UUID) (*models.Revision, error) {
+	revision := &models.Revision{Number: 1}
+	err := yaml.Unmarshal([]byte(data), &revision.Metadata)
+	return revision, err
+}
+
+func (maintainedRepository) Update(id uuid.UUID, metadata *models.Metadata, expectedRevision int, author string) (int, error) {
+	if expectedRevision != 1 {
+		return 0, app.PreconditionFailedError{Reason: "revision 1 is the current revision"}
+	}
+	return 2, nil
+}
+
+func (maintainedRepository) Delete(id uuid.UUID, expectedRevision int, author string) error {
+	if expectedRevision != 1 {
+		return app.PreconditionFailedError{Reason: "revision 1 is the current revision"}
+	}
+	return nil
+}
+
+// handedOverRepository holds the metadata of data, whose maintainers are replaced by a concurrent update
+// right after they are first read
+type handedOverRepository struct {
+	app.MetadataRepository
+	reads int
+}
+
+func (r *handedOverRepository) GetCurrentRevision(id uuid.UUID) (*models.Revision, error) {
+	r.reads++
+	revision := &models.Revision{Number: r.reads}
+	err := yaml.Unmarshal([]byte(data), &revision.Metadata)
+	if r.reads > 1 {
+		revision.Metadata.Maintainers = []*models.Maintainer{{Name: "new maintainer", Email: "new@example.com"}}
+	}
+	return revision, err
+}
+
+func (r *handedOverRepository) Update(id uuid.UUID, metadata *models.Metadata, expectedRevision int, author string) (int, error) {
+	if expectedRevision != 2 {
+		return 0, app.PreconditionFailedError{Reason: "revision 2 is the current revision"}
+	}
+	return 3, nil
+}
+
+func (r *handedOverRepository) Delete(id uuid.UUID, expectedRevision int, author string) error {
+	if expectedRevision != 2 {
+		return app.PreconditionFailedError{Reason: "revision 2 is the current revision"}
+	}
+	return nil
+}
+
+func Test_Authorize(t *testing.T) {
+	authenticator, err := auth.NewAPIKeyAuthenticator([]byte(`
+keys:
+  - {key: maintainer-key-0123, subject: maintainer, email: FirstMaintainer@hotmail.com}
+  - {key: other-key-0123456789, subject: other, email: other@example.com}
+  - {key: admin-key-0123456789, subject: admin, email: admin@example.com}
+  - {key: reader-key-012345678, subject: reader, email: reader@example.com}
+`))
+	assert.NoError(t, err)
+	roles, err := auth.NewRoles([]byte(`
+companies:
+  random inc.:
+    admins: [admin@example.com]
+    readers: [reader@example.com]
+`))
+	assert.NoError(t, err)
+	s := newTestService(t, maintainedRepository{}, validation.SimpleValidator{})
+	s.options.Authenticator = authenticator
+	*s.manager = s.manager.WithRoles(roles)
+	request := func(method string, path string, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(data))
+		req.Header.Set(auth.APIKeyHeader, key)
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, acceptJSON(req))
+		return res
+	}
+	path := "/api/metadata/8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d"
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		res := request(method, path, "other-key-0123456789")
+		assert.Equal(t, http.StatusForbidden, res.Code, method)
+		assert.JSONEq(t, `{"Code":"forbidden","ErrorMessage":"Forbidden due to other@example.com is neither a maintainer of metadata 8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d nor an admin of Random Inc."}`,
+			res.Body.String(), method)
+
+		res = request(method, path, "reader-key-012345678")
+		assert.Equal(t, http.StatusForbidden, res.Code, method)
+		assert.JSONEq(t, `{"Code":"forbidden","ErrorMessage":"Forbidden due to reader is a reader of Random Inc., writer role required"}`, res.Body.String(), method)
+
+		// maintainers are matched case-insensitively
+		assert.Equal(t, http.StatusOK, request(method, path, "maintainer-key-0123").Code, method)
+		assert.Equal(t, http.StatusOK, request(method, path, "admin-key-0123456789").Code, method)
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/api/metadata", "other-key-0123456789").Code)
+	res := request(http.MethodPost, "/api/metadata", "reader-key-012345678")
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	assert.JSONEq(t, `{"Code":"forbidden","ErrorMessage":"Forbidden due to reader is a reader of Random Inc., writer role required"}`, res.Body.String())
+}
+
+func Test_AuthorizeAgainstConcurrentUpdates(t *testing.T) {
+	authenticator, err := auth.NewAPIKeyAuthenticator([]byte("keys:\n  - {key: maintainer-key-0123, subject: maintainer, email: firstmaintainer@hotmail.com}\n"))
+	assert.NoError(t, err)
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		// the first maintainer is authorized against revision 1 but the write finds revision 2, which the
+		// first maintainer no longer maintains
+		repository := &handedOverRepository{}
+		s := newTestService(t, repository, validation.SimpleValidator{})
+		s.options.Authenticator = authenticator
+		req := httptest.NewRequest(method, "/api/metadata/8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d", strings.NewReader(data))
+		req.Header.Set(auth.APIKeyHeader, "maintainer-key-0123")
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, acceptJSON(req))
+		assert.Equal(t, http.StatusForbidden, res.Code, method)
+		assert.JSONEq(t, `{"Code":"forbidden","ErrorMessage":"Forbidden due to firstmaintainer@hotmail.com is neither a maintainer of metadata 8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d nor an admin of Random Inc."}`,
+			res.Body.String(), method)
+		assert.Equal(t, 2, repository.reads, method)
+	}
+}
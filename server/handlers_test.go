@@ -0,0 +1,343 @@
+package server
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/memoryRepo"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/validation"
+	"gopkg.in/yaml.v2"
+)
+
+// untouchedRepository fails the test on any call
+type untouchedRepository struct {
+	app.MetadataRepository
+}
+
+// insertOnlyRepository accepts inserts and fails the test on any other call
+type insertOnlyRepository struct {
+	app.MetadataRepository
+}
+
+func (insertOnlyRepository) Insert(metadata *models.Metadata, author string) (uuid.UUID, error) {
+	return uuid.MustParse("8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d"), nil
+}
+
+// emptyRepository does not hold any metadata
+type emptyRepository struct {
+	app.MetadataRepository
+}
+
+func (emptyRepository) GetCurrentRevision(id uuid.UUID) (*models.Revision, error) {
+	return nil, app.NotFoundError{Reason: "no metadata found with id " + id.String()}
+}
+
+func (emptyRepository) Delete(id uuid.UUID, expectedRevision int, author string) error {
+	return app.NotFoundError{Reason: "no metadata found with id " + id.String()}
+}
+
+func (emptyRepository) Close() error {
+	return nil
+}
+
+// searchedRepository records the criteria of the last search and finds nothing
+type searchedRepository struct {
+	app.MetadataRepository
+	criteria app.SearchCriteria
+}
+
+func (r *searchedRepository) Search(criteria app.SearchCriteria) ([]models.Metadata, int, error) {
+	r.criteria = criteria
+	return nil, 0, nil
+}
+
+func newTestService(t *testing.T, repository app.MetadataRepository, validator app.MetadataValidator) *yamlMetadataService {
+	logger := log.New(ioutil.Discard, "", 0)
+	mgr, err := app.BuildMetadataManager(repository, validator, logger)
+	assert.NoError(t, err)
+	s := &yamlMetadataService{manager: &mgr, logger: logger}
+	s.router = buildRouter(s, logger)
+	return s
+}
+
+// acceptJSON asks for a JSON response
+func acceptJSON(req *http.Request) *http.Request {
+	req.Header.Set("Accept", "application/json")
+	return req
+}
+
+func Test_ValidateHandler(t *testing.T) {
+	s := newTestService(t, untouchedRepository{}, validation.SimpleValidator{})
+
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(data)))
+	assert.Equal(t, http.StatusOK, res.Code)
+	result := models.ValidationResult{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &result))
+	assert.Equal(t, "0.0.1", result.Metadata.Version)
+	assert.Empty(t, result.Warnings)
+
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(strings.Replace(data, `"0.0.1"`, `"0.01"`, 1)))))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.JSONEq(t, `[{"Code":"validation_failed","Path":"/version","Reason":"not a semantic version: minor version 01 has a leading zero","Severity":"error","Line":14,"Column":10,
+		"Message":"Failed to validate /version due to not a semantic version: minor version 01 has a leading zero"}]`, res.Body.String())
+}
+
+func Test_CreateHandlerReturnsWarnings(t *testing.T) {
+	validator, err := validation.NewRuleValidator([]byte(`
+rules:
+  - id: title-required
+    field: title
+    required: true
+  - id: website-https
+    field: website
+    schemes: [https]
+    severity: warning
+`))
+	assert.NoError(t, err)
+	s := newTestService(t, insertOnlyRepository{}, validator)
+
+	res := httptest.NewRecorder()
+	document := strings.Replace(data, `"https://website.com"`, `"http://website.com"`, 1)
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(document)))
+	assert.Equal(t, http.StatusOK, res.Code)
+	response := models.Response{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &response))
+	assert.Equal(t, "8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d", response.Id)
+	assert.Equal(t, []models.Warning{{Path: "/website", Reason: "url scheme is not one of https", Rule: "website-https", Line: 15, Column: 10,
+		Message: "Failed to validate /website due to url scheme is not one of https"}}, response.Warnings)
+
+	// warnings are reported along with errors when the write fails
+	res = httptest.NewRecorder()
+	document = strings.Replace(document, `"Valid App 1"`, `""`, 1)
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(document))))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `"Severity":"error","Rule":"title-required"`)
+	assert.Contains(t, res.Body.String(), `"Severity":"warning","Rule":"website-https"`)
+}
+
+func Test_WriteHandlersReportMissingWarningRequiredFields(t *testing.T) {
+	repository, err := memoryrepo.GetMemoryRepository()
+	assert.NoError(t, err)
+	validator, err := validation.NewRuleValidator([]byte(`
+rules:
+  - id: title-required
+    field: title
+    required: true
+  - id: website-recommended
+    field: website
+    required: true
+    severity: warning
+  - id: license-recommended
+    field: license
+    required: true
+    severity: warning
+`))
+	assert.NoError(t, err)
+	s := newTestService(t, repository, validator)
+
+	// missing fields required by warning rules are reported but do not fail the write
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader("title: untitled website\n")))
+	assert.Equal(t, http.StatusOK, res.Code)
+	response := models.Response{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &response))
+	assert.Equal(t, []models.Warning{
+		{Path: "/website", Reason: "empty value", Rule: "website-recommended", Line: 1, Column: 1, Message: "Failed to validate /website due to empty value"},
+		{Path: "/license", Reason: "empty value", Rule: "license-recommended", Line: 1, Column: 1, Message: "Failed to validate /license due to empty value"},
+	}, response.Warnings)
+	path := "/api/metadata/" + response.Id
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), "title: untitled website")
+
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPut, path, strings.NewReader("title: still no website\nlicense: MIT\n")))
+	assert.Equal(t, http.StatusOK, res.Code)
+	response = models.Response{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &response))
+	assert.True(t, len(response.Warnings) == 1)
+	assert.Equal(t, "website-recommended", response.Warnings[0].Rule)
+}
+
+func Test_ErrorStatus(t *testing.T) {
+	s := newTestService(t, emptyRepository{}, validation.SimpleValidator{})
+
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodGet, "/api/metadata/8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d", nil)))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.JSONEq(t, `{"Code":"not_found","ErrorMessage":"no metadata found with id 8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d"}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodDelete, "/api/metadata/8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	// path parameters are located by pointer like the fields of the body and the search parameters
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodGet, "/api/metadata/latest", nil)))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.JSONEq(t, `{"Code":"validation_failed","Path":"/guid","Reason":"not a valid id","Severity":"error","Message":"Failed to validate /guid due to not a valid id"}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodGet, "/api/metadata/8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d/revisions/latest", nil)))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `"Path":"/revision"`)
+
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodGet, "/api/search?limit=ten", nil)))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `"Path":"/limit","Reason":"not a number"`)
+
+	for err, status := range map[error]int{
+		app.ConflictError{Reason: "maintainer email a@b.c is listed more than once"}:         http.StatusConflict,
+		app.PreconditionFailedError{Reason: "expected revision 1 but current revision is 2"}: http.StatusPreconditionFailed,
+		app.UnauthorizedError{Reason: "missing credentials"}:                                 http.StatusUnauthorized,
+		errors.New("something went wrong"):                                                   http.StatusInternalServerError,
+	} {
+		assert.Equal(t, status, errorStatus(err), err.Error())
+	}
+}
+
+func Test_FacetsHandlerRejectsUnknownFields(t *testing.T) {
+	s := newTestService(t, untouchedRepository{}, validation.SimpleValidator{})
+
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodGet, "/api/facets?fields=company,owner", nil)))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.JSONEq(t, `{"Code":"validation_failed","Path":"/fields","Reason":"cannot aggregate owner","Severity":"error","Message":"Failed to validate /fields due to cannot aggregate owner"}`, res.Body.String())
+}
+
+func Test_SearchHandlerParsesQueryParameterOnItsOwn(t *testing.T) {
+	repository := &searchedRepository{}
+	s := newTestService(t, repository, validation.SimpleValidator{})
+	search := func(q string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/search?q="+url.QueryEscape(q), strings.NewReader("query: company:a\n"))
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, acceptJSON(req))
+		return res
+	}
+
+	res := search("title:b OR title:c")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `(Company:"a" AND (Title:"b" OR Title:"c"))`, repository.criteria.Expr.String())
+
+	// the parameter cannot close the group of the body query, and its errors are located in the parameter
+	res = search("title:b) OR (title:c")
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `"Path":"/q"`)
+	assert.Contains(t, res.Body.String(), `"Reason":"unexpected ')' at position 8"`)
+}
+
+func Test_SearchHandlerMatchesMaintainersByName(t *testing.T) {
+	repository := &searchedRepository{}
+	s := newTestService(t, repository, validation.SimpleValidator{})
+
+	// maintainers searched by name only have no email to sanitize
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/search",
+		strings.NewReader("maintainers: [{name: firstmaintainr}]\nmatch: {name: fuzzy}\n"))))
+	assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.Equal(t, "firstmaintainr", repository.criteria.Metadata.Maintainers[0].Name)
+	assert.Equal(t, models.MatchFuzzy, repository.criteria.Match["Name"].Mode)
+}
+
+func Test_HandlersRejectOrSkipNullMaintainers(t *testing.T) {
+	repository := &searchedRepository{}
+	s := newTestService(t, repository, validation.SimpleValidator{})
+
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/metadata",
+		strings.NewReader("title: null maintainer\nversion: 1.0.0\nlicense: MIT\ndescription: none\nmaintainers: [~]\ncompany: Random Inc.\nwebsite: https://website.com\nsource: https://github.com/random/repo\n"))))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.JSONEq(t, `[{"Code":"validation_failed","Path":"/maintainers/0","Reason":"empty value","Severity":"error","Line":5,"Column":15,
+		"Message":"Failed to validate /maintainers/0 due to empty value"}]`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/search", strings.NewReader("maintainers: [~]\n"))))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func Test_HandlersAcceptMetadataWithOptionalFields(t *testing.T) {
+	repository, err := memoryrepo.GetMemoryRepository()
+	assert.NoError(t, err)
+	validator, err := validation.NewRuleValidator([]byte(`
+rules:
+  - id: description-required
+    field: description
+    required: true
+`))
+	assert.NoError(t, err)
+	s := newTestService(t, repository, validator)
+	request := func(method string, path string, body string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, httptest.NewRequest(method, path, strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, res.Code, method+" "+path)
+		return res
+	}
+
+	res := request(http.MethodPost, "/api/metadata", `
+description: a catalog entry without title, company, website, source, version or license
+maintainers:
+  - name: No Email
+  - email: noname@example.com
+`)
+	response := models.Response{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &response))
+	path := "/api/metadata/" + response.Id
+	metadata := models.Metadata{}
+	assert.NoError(t, yaml.Unmarshal(request(http.MethodGet, path, "").Body.Bytes(), &metadata))
+	assert.Equal(t, models.Metadata{Description: "a catalog entry without title, company, website, source, version or license",
+		Maintainers: []*models.Maintainer{{Name: "No Email"}, {Email: "noname@example.com"}}}, metadata)
+
+	results := models.SearchResults{}
+	assert.NoError(t, yaml.Unmarshal(request(http.MethodPost, "/api/search", "description: catalog\nsort: title\n").Body.Bytes(), &results))
+	assert.Equal(t, 1, results.Total)
+	facets := models.FacetResults{}
+	assert.NoError(t, yaml.Unmarshal(request(http.MethodPost, "/api/facets?fields=title,license,email", "description: catalog\n").Body.Bytes(), &facets))
+	assert.Equal(t, 1, facets.Total)
+	assert.Empty(t, facets.Facets["title"])
+	assert.Equal(t, []models.FacetValue{{Value: "noname@example.com", Count: 1}}, facets.Facets["email"])
+
+	request(http.MethodPut, path, "title: titled\ndescription: still without company\n")
+	request(http.MethodDelete, path, "")
+}
+
+func Test_SearchHandlerMatchesEmailOfValidatedMetadata(t *testing.T) {
+	repository, err := memoryrepo.GetMemoryRepository()
+	assert.NoError(t, err)
+	s := newTestService(t, repository, validation.SimpleValidator{})
+	document := strings.Replace(data, "email: firstmaintainer@hotmail.com", `email: "First Maintainer <Validated.Maintainer@Hotmail.com>"`, 1)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(document)))
+	assert.Equal(t, http.StatusOK, res.Code)
+	response := models.Response{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &response))
+	// the repository is shared by the tests of the package
+	defer s.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/metadata/"+response.Id, nil))
+	search := func(q string, body string) models.SearchResults {
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/search?q="+url.QueryEscape(q), strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, res.Code, q+body)
+		results := models.SearchResults{}
+		assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &results))
+		return results
+	}
+
+	// the query term matches whatever form the address is written in, like the search document does
+	for _, q := range []string{`email:validated.maintainer@hotmail.com`, `email:Validated.Maintainer@Hotmail.com`, `email:"<validated.maintainer@hotmail.com>"`} {
+		assert.Equal(t, 1, search(q, "{}").Total, q)
+	}
+	assert.Equal(t, 1, search("", "maintainers: [{email: Validated.Maintainer@hotmail.com}]").Total)
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/app"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// locateErrors sets the line and column of the validation errors whose path points into the document
+func locateErrors(document []byte, err error) error {
+	root := yamlv3.Node{}
+	if yamlv3.Unmarshal(document, &root) != nil {
+		return err
+	}
+	switch e := err.(type) {
+	case app.ValidationError:
+		return locateError(&root, e)
+	case app.AggregatedValidationError:
+		located := app.NewAggregatedValidationError()
+		for _, validationErr := range e.Errors() {
+			located.AddError(locateError(&root, validationErr))
+		}
+		return located
+	default:
+		return err
+	}
+}
+
+func locateError(root *yamlv3.Node, err app.ValidationError) app.ValidationError {
+	if err.Line != 0 || !strings.HasPrefix(err.Path, "/") {
+		return err
+	}
+	if node := resolvePointer(root, err.Path); node != nil {
+		err.Line, err.Column = node.Line, node.Column
+	}
+	return err
+}
+
+// resolvePointer returns the node a JSON pointer points to. Missing values resolve to the closest
+// existing parent, so that a missing field is reported at the mapping it is missing from
+func resolvePointer(root *yamlv3.Node, pointer string) *yamlv3.Node {
+	node := root
+	if node.Kind == yamlv3.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	for _, segment := range strings.Split(pointer, "/")[1:] {
+		segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+		for node.Kind == yamlv3.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+		child := childNode(node, segment)
+		if child == nil {
+			return node
+		}
+		node = child
+	}
+	return node
+}
+
+func childNode(node *yamlv3.Node, segment string) *yamlv3.Node {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				return node.Content[i+1]
+			}
+		}
+	case yamlv3.SequenceNode:
+		i, err := strconv.Atoi(segment)
+		if err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+var decodeErrorLine = regexp.MustCompile(`line (\d+):`)
+
+// decodeError turns an error decoding a document into a validation error on the whole document,
+// located at the line of the first problem reported by the decoder
+func decodeError(err error) app.ValidationError {
+	validationErr := app.ValidationError{Path: "", Reason: err.Error()}
+	if match := decodeErrorLine.FindStringSubmatch(err.Error()); match != nil {
+		validationErr.Line, _ = strconv.Atoi(match[1])
+	}
+	return validationErr
+}
+
+// decodeJSONError turns an error decoding a JSON document into a validation error on the whole document,
+// located at the offset reported by the decoder
+func decodeJSONError(document []byte, err error) app.ValidationError {
+	validationErr := app.ValidationError{Path: "", Reason: err.Error()}
+	var offset int64
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+	default:
+		return validationErr
+	}
+	if offset > int64(len(document)) {
+		offset = int64(len(document))
+	}
+	preceding := document[:offset]
+	validationErr.Line = bytes.Count(preceding, []byte("\n")) + 1
+	validationErr.Column = len(preceding) - bytes.LastIndexByte(preceding, '\n')
+	return validationErr
+}
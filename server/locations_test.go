@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/validation"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_LocateErrors(t *testing.T) {
+	document := []byte(`
+title: "Valid App 1"
+version: 1.02
+maintainers:
+  - name: first
+    email: first@hotmail.com
+  - name: second
+    email: second@gmail.com
+  - email: third@gmail.com
+`)
+	metadata := models.Metadata{}
+	assert.NoError(t, yaml.Unmarshal(document, &metadata))
+	err := locateErrors(document, validation.SimpleValidator{}.ValidateAndSanitize(&metadata))
+	aggregatedErr, k := err.(app.AggregatedValidationError)
+	assert.True(t, k)
+
+	located := make(map[string][2]int)
+	for _, e := range aggregatedErr.Errors() {
+		located[e.Path] = [2]int{e.Line, e.Column}
+	}
+	assert.Equal(t, [2]int{3, 10}, located["/version"])
+	// missing fields are reported at the mapping they are missing from
+	assert.Equal(t, [2]int{2, 1}, located["/company"])
+	assert.Equal(t, [2]int{9, 5}, located["/maintainers/2/name"])
+
+	body := []map[string]interface{}{}
+	encoded, _ := marshal(formatJSON, aggregatedValidationErrorBody(aggregatedErr))
+	assert.NoError(t, json.Unmarshal(encoded, &body))
+	assert.Contains(t, body, map[string]interface{}{"Code": "validation_failed", "Path": "/maintainers/2/name", "Reason": "empty value", "Severity": "error", "Line": 9.0, "Column": 5.0,
+		"Message": "Failed to validate /maintainers/2/name due to empty value"})
+
+	// errors that are not validation errors are left alone
+	other := errors.New("no metadata found")
+	assert.Equal(t, other, locateErrors(document, other))
+}
+
+func Test_DecodeError(t *testing.T) {
+	metadata := models.Metadata{}
+	err := yaml.Unmarshal([]byte("title: a\nmaintainers: 3\n"), &metadata)
+	assert.Error(t, err)
+	validationErr := decodeError(err)
+	assert.Equal(t, "", validationErr.Path)
+	assert.Equal(t, 2, validationErr.Line)
+}
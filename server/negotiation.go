@@ -0,0 +1,203 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Formats documents are exchanged in
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+// formats lists the media types of each format, the first one being used for responses
+var formats = map[string][]string{
+	formatYAML: {"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"},
+	formatJSON: {"application/json"},
+}
+
+// Codes of the negotiation errors, in addition to the ones of the app package
+const (
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codeNotAcceptable        = "not_acceptable"
+)
+
+// unsupportedMediaTypeError is returned when the body of a request is in none of the supported formats
+type unsupportedMediaTypeError struct {
+	MediaType string
+}
+
+func (e unsupportedMediaTypeError) Error() string {
+	return fmt.Sprintf("unsupported media type %s, expected application/yaml or application/json", e.MediaType)
+}
+
+func (e unsupportedMediaTypeError) Code() string {
+	return codeUnsupportedMediaType
+}
+
+// notAcceptableError is returned when a request accepts none of the supported formats
+type notAcceptableError struct {
+	Accept string
+}
+
+func (e notAcceptableError) Error() string {
+	return fmt.Sprintf("none of %s can be produced, expected application/yaml or application/json", e.Accept)
+}
+
+func (e notAcceptableError) Code() string {
+	return codeNotAcceptable
+}
+
+// negotiate rejects requests whose body is not in a supported format, or which accept none of them
+func (s *yamlMetadataService) negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, err := contentFormat(req); err != nil {
+			s.writeErrorResponse(res, req, err)
+			return
+		}
+		if _, err := acceptedFormat(req); err != nil {
+			s.writeErrorResponse(res, req, err)
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+// contentFormat returns the format of the body from the Content-Type header; bodies without one are YAML
+func contentFormat(req *http.Request) (string, error) {
+	header := req.Header.Get("Content-Type")
+	if strings.TrimSpace(header) == "" {
+		return formatYAML, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", unsupportedMediaTypeError{MediaType: header}
+	}
+	for format, mediaTypes := range formats {
+		for _, supported := range mediaTypes {
+			if mediaType == supported {
+				return format, nil
+			}
+		}
+	}
+	return "", unsupportedMediaTypeError{MediaType: mediaType}
+}
+
+// acceptedFormat returns the format of the response preferred by the Accept header. Media ranges are weighed
+// by their quality and then by their specificity; ties, as well as requests without an Accept header, are
+// answered in the format of the body
+func acceptedFormat(req *http.Request) (string, error) {
+	preferred, err := contentFormat(req)
+	if err != nil {
+		preferred = formatYAML
+	}
+	header := req.Header.Get("Accept")
+	if strings.TrimSpace(header) == "" {
+		return preferred, nil
+	}
+	candidates := []string{formatYAML, formatJSON}
+	if preferred == formatJSON {
+		candidates = []string{formatJSON, formatYAML}
+	}
+	best, bestQuality, bestSpecificity := "", 0.0, -1
+	for _, format := range candidates {
+		quality, specificity := acceptance(header, formats[format])
+		if quality > bestQuality || (quality == bestQuality && quality > 0 && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = format, quality, specificity
+		}
+	}
+	if best == "" {
+		return "", notAcceptableError{Accept: header}
+	}
+	return best, nil
+}
+
+// acceptance returns the quality the Accept header gives to the media types, along with the specificity
+// of the most specific media range matching them: 2 for the media type itself, 1 for type/* and 0 for */*
+func acceptance(header string, mediaTypes []string) (float64, int) {
+	quality, specificity := 0.0, -1
+	for _, mediaRange := range strings.Split(header, ",") {
+		accepted, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, k := params["q"]; k {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		for _, mediaType := range mediaTypes {
+			s := -1
+			switch {
+			case accepted == mediaType:
+				s = 2
+			case accepted == "*/*":
+				s = 0
+			case strings.HasSuffix(accepted, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(accepted, "*")):
+				s = 1
+			}
+			if s > specificity || (s == specificity && s >= 0 && q > quality) {
+				quality, specificity = q, s
+			}
+		}
+	}
+	return quality, specificity
+}
+
+// responseFormat returns the negotiated format of the response, falling back to the format of the body,
+// or YAML, when the request does not accept any
+func responseFormat(req *http.Request) string {
+	if format, err := acceptedFormat(req); err == nil {
+		return format
+	}
+	if format, err := contentFormat(req); err == nil {
+		return format
+	}
+	return formatYAML
+}
+
+// decode unmarshals a document of the given format; an empty JSON document leaves the target untouched,
+// as an empty YAML document does
+func decode(format string, document []byte, target interface{}) error {
+	if format == formatJSON {
+		if len(bytes.TrimSpace(document)) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(document, target); err != nil {
+			return decodeJSONError(document, err)
+		}
+		return nil
+	}
+	if err := yaml.Unmarshal(document, target); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+func marshal(format string, body interface{}) ([]byte, error) {
+	if format == formatJSON {
+		return json.Marshal(body)
+	}
+	return yaml.Marshal(body)
+}
+
+// writeResponse renders the body in the negotiated format
+func (s *yamlMetadataService) writeResponse(res http.ResponseWriter, req *http.Request, body interface{}) {
+	format := responseFormat(req)
+	responseBody, err := marshal(format, body)
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	res.Header().Set("Content-Type", formats[format][0])
+	res.Write(responseBody)
+}
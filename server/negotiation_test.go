@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/validation"
+	"gopkg.in/yaml.v2"
+)
+
+var jsonData = `{
+	"title": "Valid App 1",
+	"version": "0.0.1",
+	"company": "Random Inc.",
+	"website": "https://website.com",
+	"source": "https://github.com/random/repo",
+	"license": "Apache-2.0",
+	"description": "aasasdfadfs",
+	"maintainers": [
+		{"name": "firstmaintainer app1", "email": "firstmaintainer@hotmail.com"}
+	]
+}`
+
+func Test_AcceptedFormat(t *testing.T) {
+	for _, test := range []struct {
+		contentType, accept, format string
+	}{
+		{"", "", formatYAML},
+		{"application/json", "", formatJSON},
+		{"application/json; charset=utf-8", "*/*", formatJSON},
+		{"", "application/json", formatJSON},
+		{"application/json", "text/yaml", formatYAML},
+		{"", "application/json, */*", formatJSON},
+		{"", "application/json;q=0.5, application/yaml", formatYAML},
+		{"", "application/*;q=0.2, application/json;q=0", formatYAML},
+		{"application/json", "text/*, application/json;q=0.9", formatYAML},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+		req.Header.Set("Content-Type", test.contentType)
+		req.Header.Set("Accept", test.accept)
+		format, err := acceptedFormat(req)
+		assert.NoError(t, err, test.accept)
+		assert.Equal(t, test.format, format, test.accept)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	req.Header.Set("Accept", "text/html, application/json;q=0")
+	_, err := acceptedFormat(req)
+	assert.EqualError(t, err, "none of text/html, application/json;q=0 can be produced, expected application/yaml or application/json")
+}
+
+func Test_JSONHandlers(t *testing.T) {
+	s := newTestService(t, insertOnlyRepository{}, validation.SimpleValidator{})
+	s.options.Strict = true
+
+	req := httptest.NewRequest(http.MethodPost, "/api/metadata", strings.NewReader(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":"8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d"}`, res.Body.String())
+
+	// YAML documents can be answered in JSON, and JSON documents in YAML
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(data))))
+	assert.Equal(t, http.StatusOK, res.Code)
+	result := models.ValidationResult{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &result))
+	assert.Equal(t, "0.0.1", result.Metadata.Version)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/yaml")
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/yaml", res.Header().Get("Content-Type"))
+	result = models.ValidationResult{}
+	assert.NoError(t, yaml.Unmarshal(res.Body.Bytes(), &result))
+	assert.Equal(t, "Valid App 1", result.Metadata.Title)
+
+	// errors are located in JSON documents as well
+	req = httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(strings.Replace(jsonData, `"title"`, `"titel"`, 1)))
+	req.Header.Set("Content-Type", "application/json")
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.JSONEq(t, `[{"Code":"validation_failed","Path":"/titel","Reason":"unknown field titel","Severity":"error","Line":2,"Column":2,
+		"Message":"Failed to validate /titel due to unknown field titel"}]`, res.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(strings.Replace(jsonData, `"0.0.1"`, `1`, 1)))
+	req.Header.Set("Content-Type", "application/json")
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `"Line":3`)
+}
+
+func Test_UnsupportedFormats(t *testing.T) {
+	s := newTestService(t, untouchedRepository{}, validation.SimpleValidator{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(data))
+	req.Header.Set("Content-Type", "text/plain")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+	assert.Equal(t, "application/yaml", res.Header().Get("Content-Type"))
+	assert.Equal(t, "Code: unsupported_media_type\nErrorMessage: unsupported media type text/plain, expected application/yaml or application/json\n", res.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/html")
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotAcceptable, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"Code":"not_acceptable","ErrorMessage":"none of text/html can be produced, expected application/yaml or application/json"}`, res.Body.String())
+}
+
+func Test_SearchRequestUnmarshalJSON(t *testing.T) {
+	request := models.SearchRequest{}
+	err := json.Unmarshal([]byte(`{"company": "microsoft", "match": {"company": "ignorecase", "title": {"mode": "glob", "ignoreCase": true}}}`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, "microsoft", request.Company)
+	assert.Equal(t, models.FieldMatch{Mode: models.MatchExact, IgnoreCase: true}, request.Match["company"])
+	assert.Equal(t, models.FieldMatch{Mode: models.MatchGlob, IgnoreCase: true}, request.Match["title"])
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/validation"
+)
+
+// blockingRepository holds inserts until released, and records when it is closed
+type blockingRepository struct {
+	app.MetadataRepository
+	inserting chan struct{}
+	release   chan struct{}
+	closed    chan struct{}
+}
+
+func newBlockingRepository() *blockingRepository {
+	return &blockingRepository{inserting: make(chan struct{}), release: make(chan struct{}), closed: make(chan struct{})}
+}
+
+func (r *blockingRepository) Insert(metadata *models.Metadata, author string) (uuid.UUID, error) {
+	close(r.inserting)
+	<-r.release
+	return uuid.New(), nil
+}
+
+func (r *blockingRepository) Close() error {
+	close(r.closed)
+	return nil
+}
+
+func Test_ShutdownDrainsRequests(t *testing.T) {
+	repository := newBlockingRepository()
+	s := newTestService(t, repository, validation.SimpleValidator{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	signals := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- s.serve(listener, signals)
+	}()
+
+	responses := make(chan int, 1)
+	go func() {
+		res, err := http.Post("http://"+listener.Addr().String()+"/api/metadata", "application/yaml", strings.NewReader(data))
+		if err != nil {
+			responses <- 0
+			return
+		}
+		res.Body.Close()
+		responses <- res.StatusCode
+	}()
+	<-repository.inserting
+	signals <- syscall.SIGTERM
+
+	// the in-flight request holds the shutdown, and the repository stays open until it completes
+	select {
+	case <-stopped:
+		t.Fatal("stopped before the in-flight request completed")
+	case <-repository.closed:
+		t.Fatal("closed the repository before the in-flight request completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(repository.release)
+	assert.Equal(t, http.StatusOK, <-responses)
+	assert.NoError(t, <-stopped)
+	<-repository.closed
+
+	// new connections are refused
+	_, err = net.Dial("tcp", listener.Addr().String())
+	assert.Error(t, err)
+}
+
+func Test_ShutdownDeadline(t *testing.T) {
+	repository := newBlockingRepository()
+	s := newTestService(t, repository, validation.SimpleValidator{})
+	s.options.ShutdownTimeout = 50 * time.Millisecond
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	signals := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- s.serve(listener, signals)
+	}()
+
+	go http.Post("http://"+listener.Addr().String()+"/api/metadata", "application/yaml", strings.NewReader(data))
+	<-repository.inserting
+	signals <- syscall.SIGINT
+
+	// the repository is closed even though the request did not complete in time
+	assert.Equal(t, context.DeadlineExceeded, <-stopped)
+	<-repository.closed
+	close(repository.release)
+}
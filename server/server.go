@@ -1,17 +1,25 @@
 package server
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/auth"
 	"gitlab.com/erikwu09/yamlr/models"
-	"gopkg.in/yaml.v2"
 )
 
 type yamlMetadataService struct {
@@ -19,21 +27,128 @@ type yamlMetadataService struct {
 	port    int
 	manager *app.MetadataManager
 	logger  *log.Logger
+	options Options
+	// certificates is set when serving HTTPS
+	certificates *certificateReloader
 }
 
-func (s *yamlMetadataService) Run() {
-	p := strconv.Itoa(s.port)
+// Options configures the YAML Metadata Service
+type Options struct {
+	// Strict rejects documents with unknown or duplicate keys instead of ignoring them
+	Strict bool
+	// ReadTimeout, WriteTimeout and IdleTimeout bound the time spent reading a request, writing its
+	// response and waiting for the next request of a kept-alive connection; 0 means no timeout
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout bounds the time in-flight requests are given to complete on shutdown; 0 means no deadline
+	ShutdownTimeout time.Duration
+	// TLSCertFile and TLSKeyFile serve HTTPS instead of HTTP when set. Clients have to present a certificate
+	// signed by one of the CAs of the optional TLSClientCAFile bundle. The files are checked for changes every
+	// TLSReloadInterval, DefaultTLSReloadInterval if 0
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	TLSReloadInterval time.Duration
+	// Authenticator authenticates the requests; every request is anonymous if nil
+	Authenticator auth.Authenticator
+	// AnonymousReads lets requests without credentials read and search metadata when an Authenticator is set
+	AnonymousReads bool
+}
+
+// Run serves requests until SIGINT or SIGTERM is received, then stops accepting connections, drains
+// the in-flight requests and closes the repository
+func (s *yamlMetadataService) Run() error {
 	s.logger.Println("Starting service...")
-	s.logger.Fatal(http.ListenAndServe(":"+p, s.router))
+	if s.options.TLSCertFile != "" || s.options.TLSKeyFile != "" || s.options.TLSClientCAFile != "" {
+		certificates, err := newCertificateReloader(s.options.TLSCertFile, s.options.TLSKeyFile, s.options.TLSClientCAFile, s.logger)
+		if err != nil {
+			s.closeManager()
+			return err
+		}
+		s.certificates = certificates
+	}
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(s.port))
+	if err != nil {
+		s.closeManager()
+		return err
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	return s.serve(listener, signals)
+}
+
+// serve serves requests on the listener, over TLS if certificates are set, until a signal is received
+func (s *yamlMetadataService) serve(listener net.Listener, signals <-chan os.Signal) error {
+	server := &http.Server{
+		Handler:      s.router,
+		ReadTimeout:  s.options.ReadTimeout,
+		WriteTimeout: s.options.WriteTimeout,
+		IdleTimeout:  s.options.IdleTimeout,
+		ErrorLog:     s.logger,
+	}
+	errs := make(chan error, 1)
+	if s.certificates == nil {
+		go func() {
+			errs <- server.Serve(listener)
+		}()
+	} else {
+		interval := s.options.TLSReloadInterval
+		if interval <= 0 {
+			interval = DefaultTLSReloadInterval
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		go s.certificates.watch(interval, stop)
+		// the certificate is served by the configuration rather than loaded from files by ServeTLS
+		server.TLSConfig = s.certificates.config()
+		go func() {
+			errs <- server.ServeTLS(listener, "", "")
+		}()
+	}
+	select {
+	case err := <-errs:
+		s.closeManager()
+		return err
+	case sig := <-signals:
+		s.logger.Printf("received %s, shutting down", sig)
+	}
+
+	ctx := context.Background()
+	if s.options.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.ShutdownTimeout)
+		defer cancel()
+	}
+	// Shutdown closes the listener and returns once every in-flight request completed
+	err := server.Shutdown(ctx)
+	if err != nil {
+		s.logger.Printf("in-flight requests did not complete in time: %s", err.Error())
+	}
+	if closeErr := s.closeManager(); err == nil {
+		err = closeErr
+	}
+	s.logger.Println("service stopped")
+	return err
+}
+
+// closeManager closes the repository, flushing any durable storage
+func (s *yamlMetadataService) closeManager() error {
+	err := s.manager.Close()
+	if err != nil {
+		s.logger.Printf("failed to close the repository: %s", err.Error())
+	}
+	return err
 }
 
 // singleton instance of service
 var service *yamlMetadataService
 
 // Instantiates the YAML Metadata Service
-func BuildYamlApp(port int, manager *app.MetadataManager, logger *log.Logger) *yamlMetadataService {
+func BuildYamlApp(port int, manager *app.MetadataManager, logger *log.Logger, options Options) *yamlMetadataService {
 	if service == nil {
-		service = &yamlMetadataService{port: port}
+		service = &yamlMetadataService{port: port, options: options}
 		service.router = buildRouter(service, logger)
 		service.manager = manager
 		service.logger = logger
@@ -44,64 +159,117 @@ func BuildYamlApp(port int, manager *app.MetadataManager, logger *log.Logger) *y
 func buildRouter(app *yamlMetadataService, logger *log.Logger) *mux.Router {
 	logger.Println("registering handlers routes")
 	router := mux.NewRouter()
+	router.Use(app.negotiate, app.authenticate)
 	router.HandleFunc("/api/metadata", app.createMetadataHandler).Methods("POST")
-	router.HandleFunc("/api/search", app.searchMetadataHandler).Methods("POST")
+	router.HandleFunc("/api/validate", app.validateMetadataHandler).Methods("POST")
+	router.HandleFunc("/api/search", app.searchMetadataHandler).Methods("POST", "GET")
+	router.HandleFunc("/api/facets", app.getFacetsHandler).Methods("GET", "POST")
 	router.HandleFunc("/api/metadata/{guid}", app.updateMetadataHandler).Methods("PUT")
 	router.HandleFunc("/api/metadata/{guid}", app.getMetadataHandler).Methods("GET")
+	router.HandleFunc("/api/metadata/{guid}", app.deleteMetadataHandler).Methods("DELETE")
+	router.HandleFunc("/api/metadata/{guid}/revisions", app.getRevisionsHandler).Methods("GET")
+	router.HandleFunc("/api/metadata/{guid}/revisions/{revision}", app.getRevisionHandler).Methods("GET")
 	return router
 }
 
 func (s *yamlMetadataService) createMetadataHandler(res http.ResponseWriter, req *http.Request) {
 	s.logger.Println("received request for Create Metadata")
-	metadata, err := s.getPayload(req)
+	metadata, document, err := s.getPayload(req)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	id, err := s.manager.CreateMetadata(*metadata)
+	id, warnings, err := s.manager.CreateMetadata(req.Context(), *metadata)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, locateErrors(document, err))
 		return
 	}
-	s.logger.Printf("new metadata created with id %s \n ", id.String())
-	res.Write([]byte(id.String()))
+	s.writeResponse(res, req, &models.Response{Id: id.String(), Warnings: formatWarnings(document, warnings)})
+}
+
+func (s *yamlMetadataService) validateMetadataHandler(res http.ResponseWriter, req *http.Request) {
+	s.logger.Println("received request for Validate Metadata")
+	metadata, document, err := s.getPayload(req)
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	result, warnings, err := s.manager.ValidateMetadata(req.Context(), *metadata)
+	if err != nil {
+		s.writeErrorResponse(res, req, locateErrors(document, err))
+		return
+	}
+	s.writeResponse(res, req, &models.ValidationResult{Metadata: result, Warnings: formatWarnings(document, warnings)})
 }
 
 func (s *yamlMetadataService) searchMetadataHandler(res http.ResponseWriter, req *http.Request) {
 	s.logger.Println("received request for Search Metadata")
-	metadata, err := s.getPayload(req)
+	request, document, err := s.getSearchPayload(req)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
+		return
 	}
-	results, err := s.manager.SearchMetadata(*metadata)
+	results, err := s.manager.SearchMetadata(req.Context(), *request)
+	if err != nil {
+		s.writeErrorResponse(res, req, locateErrors(document, err))
+		return
+	}
+	s.writeResponse(res, req, &results)
+}
+
+func (s *yamlMetadataService) getFacetsHandler(res http.ResponseWriter, req *http.Request) {
+	s.logger.Println("received request for Get Facets")
+	request, document, err := s.getSearchPayload(req)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	responseBody, err := yaml.Marshal(&results)
+	results, err := s.manager.GetFacets(req.Context(), req.URL.Query().Get("fields"), *request)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, locateErrors(document, err))
 		return
 	}
-	res.Write(responseBody)
+	s.writeResponse(res, req, &results)
 }
 
 func (s *yamlMetadataService) updateMetadataHandler(res http.ResponseWriter, req *http.Request) {
 	s.logger.Println("received request for Update Metadata")
 	params := mux.Vars(req)
-	id, err := uuid.Parse(params["guid"])
+	id, err := parseID(params["guid"])
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	metadata, err := s.getPayload(req)
+	expectedRevision, err := parseIfMatch(req.Header.Get("If-Match"))
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	err = s.manager.UpdateMetadata(*metadata, id)
+	metadata, document, err := s.getPayload(req)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	revision, warnings, err := s.manager.UpdateMetadata(req.Context(), *metadata, id, expectedRevision)
+	if err != nil {
+		s.writeErrorResponse(res, req, locateErrors(document, err))
+		return
+	}
+	res.Header().Set("ETag", formatETag(revision))
+	s.writeResponse(res, req, &models.Response{Id: id.String(), Warnings: formatWarnings(document, warnings)})
+}
+
+func (s *yamlMetadataService) deleteMetadataHandler(res http.ResponseWriter, req *http.Request) {
+	s.logger.Println("received request for Delete Metadata")
+	params := mux.Vars(req)
+	id, err := parseID(params["guid"])
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	err = s.manager.DeleteMetadata(req.Context(), id)
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
 		return
 	}
 }
@@ -109,89 +277,248 @@ func (s *yamlMetadataService) updateMetadataHandler(res http.ResponseWriter, req
 func (s *yamlMetadataService) getMetadataHandler(res http.ResponseWriter, req *http.Request) {
 	s.logger.Println("received request for Get Metadata")
 	params := mux.Vars(req)
-	id, err := uuid.Parse(params["guid"])
+	id, err := parseID(params["guid"])
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	result, err := s.manager.GetMetadata(id)
+	result, revision, err := s.manager.GetMetadata(req.Context(), id)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	responseBody, err := yaml.Marshal(result)
+	res.Header().Set("ETag", formatETag(revision))
+	s.writeResponse(res, req, result)
+}
+
+func (s *yamlMetadataService) getRevisionsHandler(res http.ResponseWriter, req *http.Request) {
+	s.logger.Println("received request for Get Revisions")
+	params := mux.Vars(req)
+	id, err := parseID(params["guid"])
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	results, err := s.manager.GetRevisions(req.Context(), id)
 	if err != nil {
-		s.writeErrorResponse(res, err)
+		s.writeErrorResponse(res, req, err)
 		return
 	}
-	res.Write(responseBody)
+	s.writeResponse(res, req, &results)
+}
 
+func (s *yamlMetadataService) getRevisionHandler(res http.ResponseWriter, req *http.Request) {
+	s.logger.Println("received request for Get Revision")
+	params := mux.Vars(req)
+	id, err := parseID(params["guid"])
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	number, err := strconv.Atoi(params["revision"])
+	if err != nil {
+		s.writeErrorResponse(res, req, app.ValidationError{Path: app.Pointer("revision"), Reason: "revision is not a number"})
+		return
+	}
+	result, err := s.manager.GetRevision(req.Context(), id, number)
+	if err != nil {
+		s.writeErrorResponse(res, req, err)
+		return
+	}
+	s.writeResponse(res, req, result)
 }
-func (s *yamlMetadataService) writeErrorResponse(res http.ResponseWriter, err error) {
+
+// writeErrorResponse renders the error in the negotiated format, along with its code
+func (s *yamlMetadataService) writeErrorResponse(res http.ResponseWriter, req *http.Request, err error) {
 	s.logger.Printf("encountered error: %s", err.Error())
-	res.Header().Set("Content-Type", "application/json")
-	var errorBody []byte
-	switch err.(type) {
+	var body interface{}
+	switch e := err.(type) {
 	case app.ValidationError:
-		res.WriteHeader(http.StatusBadRequest)
-		errorBody = formatValidationError(err.(app.ValidationError))
+		body = validationErrorBody(e)
 	case app.AggregatedValidationError:
-		res.WriteHeader(http.StatusBadRequest)
-		errorBody = formatAggregatedValidationError(err.(app.AggregatedValidationError))
+		body = aggregatedValidationErrorBody(e)
 	default:
-		res.WriteHeader(http.StatusInternalServerError)
-		body := struct {
-			ErrorMessage string
-		}{
-			err.Error(),
-		}
-		errorBody, _ = json.Marshal(body)
+		body = errorResponse{Code: app.ErrorCode(err), ErrorMessage: err.Error()}
 	}
-	res.Write([]byte(errorBody))
+	format := responseFormat(req)
+	errorBody, _ := marshal(format, body)
+	res.Header().Set("Content-Type", formats[format][0])
+	res.WriteHeader(errorStatus(err))
+	res.Write(errorBody)
 }
 
-func formatAggregatedValidationError(err app.AggregatedValidationError) []byte {
-	errorMessage := make([]interface{}, 0, len(err.Errors()))
+// errorStatus maps the error taxonomy to HTTP status codes
+func errorStatus(err error) int {
+	switch app.ErrorCode(err) {
+	case app.CodeValidationFailed:
+		return http.StatusBadRequest
+	case app.CodeNotFound:
+		return http.StatusNotFound
+	case app.CodeConflict:
+		return http.StatusConflict
+	case app.CodePreconditionFailed:
+		return http.StatusPreconditionFailed
+	case app.CodeUnauthorized:
+		return http.StatusUnauthorized
+	case app.CodeForbidden:
+		return http.StatusForbidden
+	case codeUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case codeNotAcceptable:
+		return http.StatusNotAcceptable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorResponse and validationErrorResponse use the same keys in every format
+type errorResponse struct {
+	Code         string `yaml:"Code"`
+	ErrorMessage string `yaml:"ErrorMessage"`
+}
+
+func aggregatedValidationErrorBody(err app.AggregatedValidationError) []validationErrorResponse {
+	body := make([]validationErrorResponse, 0, len(err.Errors()))
 	for _, e := range err.Errors() {
-		body := struct {
-			Path    string
-			Reason  string
-			Message string
-		}{
-			Path:    e.Path,
-			Reason:  e.Reason,
-			Message: e.Error(),
-		}
-		errorMessage = append(errorMessage, body)
+		body = append(body, validationErrorBody(e))
 	}
-	errorBody, _ := json.Marshal(errorMessage)
-	return errorBody
+	return body
 }
 
-func formatValidationError(err app.ValidationError) []byte {
-	body := struct {
-		Path    string
-		Reason  string
-		Message string
-	}{
-		Path:    err.Path,
-		Reason:  err.Reason,
-		Message: err.Error(),
+type validationErrorResponse struct {
+	Code     string `yaml:"Code"`
+	Path     string `yaml:"Path"`
+	Reason   string `yaml:"Reason"`
+	Severity string `yaml:"Severity"`
+	Rule     string `json:",omitempty" yaml:"Rule,omitempty"`
+	Line     int    `json:",omitempty" yaml:"Line,omitempty"`
+	Column   int    `json:",omitempty" yaml:"Column,omitempty"`
+	Message  string `yaml:"Message"`
+}
+
+func validationErrorBody(err app.ValidationError) validationErrorResponse {
+	severity := app.SeverityError
+	if err.IsWarning() {
+		severity = app.SeverityWarning
+	}
+	return validationErrorResponse{
+		Code:     err.Code(),
+		Path:     err.Path,
+		Reason:   err.Reason,
+		Severity: severity,
+		Rule:     err.Rule,
+		Line:     err.Line,
+		Column:   err.Column,
+		Message:  err.Error(),
 	}
-	errorBody, _ := json.Marshal(body)
-	return errorBody
 }
 
-func (s *yamlMetadataService) getPayload(req *http.Request) (*models.Metadata, error) {
+// formatWarnings locates the warnings of a write in its document
+func formatWarnings(document []byte, warnings []app.ValidationError) []models.Warning {
+	if len(warnings) == 0 {
+		return nil
+	}
+	located := app.NewAggregatedValidationError()
+	for _, warning := range warnings {
+		located.AddError(warning)
+	}
+	result := make([]models.Warning, 0, len(warnings))
+	for _, warning := range locateErrors(document, located).(app.AggregatedValidationError).Errors() {
+		result = append(result, models.Warning{Path: warning.Path, Reason: warning.Reason, Rule: warning.Rule,
+			Line: warning.Line, Column: warning.Column, Message: warning.Error()})
+	}
+	return result
+}
+
+// getPayload reads the metadata from the body, in the format of its Content-Type, and returns it along with the raw document
+func (s *yamlMetadataService) getPayload(req *http.Request) (*models.Metadata, []byte, error) {
 	reqBody, err := ioutil.ReadAll(req.Body)
 	s.logger.Printf("received payload: %s \n", reqBody)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	format, err := contentFormat(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.options.Strict {
+		if err := checkKeys(reqBody, reflect.TypeOf(models.Metadata{})); err != nil {
+			return nil, nil, err
+		}
 	}
 	metadata := models.Metadata{}
-	err = yaml.Unmarshal(reqBody, &metadata)
+	if err := decode(format, reqBody, &metadata); err != nil {
+		return nil, nil, err
+	}
+	return &metadata, reqBody, nil
+}
+
+// parseID parses the guid of a path
+func parseID(guid string) (uuid.UUID, error) {
+	id, err := uuid.Parse(guid)
 	if err != nil {
-		return nil, app.ValidationError{Path: ".", Reason: err.Error()}
+		return id, app.ValidationError{Path: app.Pointer("guid"), Reason: "not a valid id"}
+	}
+	return id, nil
+}
+
+// formatETag derives a strong ETag from a revision number
+func formatETag(revision int) string {
+	return fmt.Sprintf("\"%d\"", revision)
+}
+
+// parseIfMatch returns the revision expected by an If-Match header, or 0 if any revision is accepted
+func parseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	revision, err := strconv.Atoi(strings.Trim(header, "\""))
+	if err != nil || revision <= 0 || !strings.HasPrefix(header, "\"") || !strings.HasSuffix(header, "\"") {
+		return 0, app.PreconditionFailedError{Reason: fmt.Sprintf("If-Match %s does not match any revision", header)}
+	}
+	return revision, nil
+}
+
+// getSearchPayload reads the search document from the body. A query expression passed as the q
+// parameter is combined with the one of the body, if any, and the sort, limit, offset and cursor
+// parameters replace the ones of the body. The raw document is returned as well
+func (s *yamlMetadataService) getSearchPayload(req *http.Request) (*models.SearchRequest, []byte, error) {
+	reqBody, err := ioutil.ReadAll(req.Body)
+	s.logger.Printf("received payload: %s \n", reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	format, err := contentFormat(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.options.Strict {
+		if err := checkKeys(reqBody, reflect.TypeOf(models.SearchRequest{})); err != nil {
+			return nil, nil, err
+		}
+	}
+	request := models.SearchRequest{}
+	if err := decode(format, reqBody, &request); err != nil {
+		return nil, nil, err
+	}
+	values := req.URL.Query()
+	request.QueryParameter = values.Get("q")
+	// paging parameters override the ones of the body
+	if sort := values.Get("sort"); sort != "" {
+		request.Sort = sort
+	}
+	if cursor := values.Get("cursor"); cursor != "" {
+		request.Cursor = cursor
+	}
+	for name, target := range map[string]*int{"limit": &request.Limit, "offset": &request.Offset} {
+		if value := values.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, nil, app.ValidationError{Path: app.Pointer(name), Reason: "not a number"}
+			}
+			*target = n
+		}
 	}
-	return &metadata, nil
+	return &request, reqBody, nil
 }
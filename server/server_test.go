@@ -32,3 +32,36 @@ func Test_YamlUnmarshal(t *testing.T) {
 	assert.True(t, len(metadata.Maintainers) == 2)
 	assert.True(t, metadata.Maintainers[0].Name == "firstmaintainer app1")
 }
+
+func Test_ParseIfMatch(t *testing.T) {
+	revision, err := parseIfMatch("")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, revision)
+	revision, err = parseIfMatch("*")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, revision)
+	revision, err = parseIfMatch(formatETag(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, revision)
+	_, err = parseIfMatch("3")
+	assert.Error(t, err)
+	_, err = parseIfMatch(`W/"3"`)
+	assert.Error(t, err)
+}
+
+func Test_SearchRequestUnmarshal(t *testing.T) {
+	request := models.SearchRequest{}
+	err := yaml.Unmarshal([]byte(`
+company: microsoft
+title: azure*
+match:
+  company: ignorecase
+  title:
+    mode: glob
+    ignoreCase: true
+`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, "microsoft", request.Company)
+	assert.Equal(t, models.FieldMatch{Mode: models.MatchExact, IgnoreCase: true}, request.Match["company"])
+	assert.Equal(t, models.FieldMatch{Mode: models.MatchGlob, IgnoreCase: true}, request.Match["title"])
+}
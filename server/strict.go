@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/app"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// checkKeys rejects keys of the document that do not map to any field of the target type, as well as keys
+// set more than once in the same mapping, which the decoder would otherwise silently drop
+func checkKeys(document []byte, target reflect.Type) error {
+	root := yamlv3.Node{}
+	if yamlv3.Unmarshal(document, &root) != nil || len(root.Content) == 0 {
+		// malformed documents are reported by the decoder
+		return nil
+	}
+	aggregateErr := app.NewAggregatedValidationError()
+	checkNode(root.Content[0], target, nil, &aggregateErr)
+	if len(aggregateErr.Errors()) == 0 {
+		return nil
+	}
+	return aggregateErr
+}
+
+func checkNode(node *yamlv3.Node, t reflect.Type, path []interface{}, aggregateErr *app.AggregatedValidationError) {
+	for node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		var fields map[string]reflect.Type
+		if t.Kind() == reflect.Struct {
+			fields = yamlFields(t)
+		} else if t.Kind() != reflect.Map {
+			return
+		}
+		seen := make(map[string]int)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := append(append([]interface{}{}, path...), key.Value)
+			if line, k := seen[key.Value]; k {
+				aggregateErr.AddError(app.ValidationError{Path: app.Pointer(keyPath...), Line: key.Line, Column: key.Column,
+					Reason: fmt.Sprintf("duplicate key %s, first set at line %d", key.Value, line)})
+				continue
+			}
+			seen[key.Value] = key.Line
+			if t.Kind() == reflect.Map {
+				checkNode(value, t.Elem(), keyPath, aggregateErr)
+				continue
+			}
+			fieldType, k := fields[key.Value]
+			if !k {
+				aggregateErr.AddError(app.ValidationError{Path: app.Pointer(keyPath...), Line: key.Line, Column: key.Column,
+					Reason: fmt.Sprintf("unknown field %s", key.Value)})
+				continue
+			}
+			checkNode(value, fieldType, keyPath, aggregateErr)
+		}
+	case yamlv3.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range node.Content {
+			checkNode(item, t.Elem(), append(append([]interface{}{}, path...), i), aggregateErr)
+		}
+	}
+}
+
+// yamlFields returns the types of the fields of a struct keyed by their yaml name, inlined structs included
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		inline := false
+		for _, flag := range tag[1:] {
+			inline = inline || flag == "inline"
+		}
+		if inline {
+			for name, fieldType := range yamlFields(field.Type) {
+				fields[name] = fieldType
+			}
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/validation"
+)
+
+func Test_CheckKeys(t *testing.T) {
+	assert.NoError(t, checkKeys([]byte(data), reflect.TypeOf(models.Metadata{})))
+
+	err := checkKeys([]byte(`
+title: AKS
+licence: MIT
+maintainers:
+  - name: first
+    emial: first@hotmail.com
+title: Azure Kubernetes Service
+`), reflect.TypeOf(models.Metadata{}))
+	aggregatedErr, k := err.(app.AggregatedValidationError)
+	assert.True(t, k)
+	assert.Equal(t, []app.ValidationError{
+		{Path: "/licence", Reason: "unknown field licence", Line: 3, Column: 1},
+		{Path: "/maintainers/0/emial", Reason: "unknown field emial", Line: 6, Column: 5},
+		{Path: "/title", Reason: "duplicate key title, first set at line 2", Line: 7, Column: 1},
+	}, aggregatedErr.Errors())
+
+	// inlined metadata, maps and match shorthands
+	assert.NoError(t, checkKeys([]byte(`
+company: Microsoft
+query: license:MIT
+match:
+  company: ignorecase
+  title:
+    mode: fuzzy
+    maxDistance: 1
+`), reflect.TypeOf(models.SearchRequest{})))
+	err = checkKeys([]byte("match:\n  title:\n    mod: fuzzy\n"), reflect.TypeOf(models.SearchRequest{}))
+	assert.EqualError(t, err, "error at /match/title/mod \n")
+}
+
+func Test_StrictModeOption(t *testing.T) {
+	document := data + "licence: MIT\n"
+
+	s := newTestService(t, untouchedRepository{}, validation.SimpleValidator{})
+	s.options.Strict = true
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, acceptJSON(httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(document))))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `"Path":"/licence","Reason":"unknown field licence","Severity":"error","Line":16`)
+
+	s.options.Strict = false
+	res = httptest.NewRecorder()
+	s.router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(document)))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
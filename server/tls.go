@@ -0,0 +1,142 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultTLSReloadInterval is the default interval at which the certificate files are checked for changes
+const DefaultTLSReloadInterval = 10 * time.Second
+
+// certificateReloader serves the certificate, and the client CA bundle if any, loaded from files, and loads
+// them again when the files change, so that renewed certificates are picked up without a restart
+type certificateReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	logger       *log.Logger
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	// modTimes of the files the certificate and client CAs were loaded from
+	modTimes []time.Time
+}
+
+// newCertificateReloader loads the certificate and client CA bundle. clientCAFile is optional
+func newCertificateReloader(certFile string, keyFile string, clientCAFile string, logger *log.Logger) (*certificateReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("the TLS certificate and key must be set together")
+	}
+	r := &certificateReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile, logger: logger}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certificateReloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+// reload loads the files again if any of them changed since they were last loaded, and returns whether
+// they were. The current certificate is kept when the new one cannot be loaded, e.g. when the certificate
+// was renewed but not its key yet, and the load is attempted again on the next call
+func (r *certificateReloader) reload() (bool, error) {
+	files := r.files()
+	modTimes := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	r.mutex.RLock()
+	unchanged := r.modTimes != nil
+	for i := range r.modTimes {
+		unchanged = unchanged && r.modTimes[i].Equal(modTimes[i])
+	}
+	r.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		bundle, err := ioutil.ReadFile(r.clientCAFile)
+		if err != nil {
+			return false, err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(bundle) {
+			return false, fmt.Errorf("no certificate found in %s", r.clientCAFile)
+		}
+	}
+	r.mutex.Lock()
+	r.certificate, r.clientCAs, r.modTimes = &certificate, clientCAs, modTimes
+	r.mutex.Unlock()
+	return true, nil
+}
+
+// watch reloads the files every interval until stop is closed
+func (r *certificateReloader) watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				r.logger.Printf("failed to reload the TLS certificate, keeping the current one: %s", err.Error())
+			} else if reloaded {
+				r.logger.Printf("reloaded the TLS certificate from %s", r.certFile)
+			}
+		}
+	}
+}
+
+func (r *certificateReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.certificate, nil
+}
+
+// config returns a TLS configuration serving the current certificate. Clients have to present a certificate
+// signed by one of the client CAs when a bundle is set
+func (r *certificateReloader) config() *tls.Config {
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2", "http/1.1"},
+		GetCertificate: r.getCertificate,
+	}
+	if r.clientCAFile != "" {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			clientConfig := config.Clone()
+			clientConfig.GetConfigForClient = nil
+			r.mutex.RLock()
+			clientConfig.ClientCAs = r.clientCAs
+			r.mutex.RUnlock()
+			return clientConfig, nil
+		}
+	}
+	return config
+}
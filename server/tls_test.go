@@ -0,0 +1,148 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/validation"
+)
+
+// certificateAuthority issues certificates for the tests
+type certificateAuthority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+func newCertificateAuthority(t *testing.T) *certificateAuthority {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "yamlr test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &certificateAuthority{certificate: certificate, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for the common name, valid for 127.0.0.1
+func (ca *certificateAuthority) issue(t *testing.T, serial int64, commonName string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeFile(t *testing.T, path string, content []byte, modTime time.Time) {
+	assert.NoError(t, ioutil.WriteFile(path, content, 0600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func Test_TLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca := newCertificateAuthority(t)
+	certFile, keyFile, caFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "ca.pem")
+	cert, key := ca.issue(t, 2, "first", x509.ExtKeyUsageServerAuth)
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, certFile, cert, modTime)
+	writeFile(t, keyFile, key, modTime)
+	writeFile(t, caFile, ca.pem, modTime)
+	clientCert, clientKey := ca.issue(t, 3, "client", x509.ExtKeyUsageClientAuth)
+	clientCertificate, err := tls.X509KeyPair(clientCert, clientKey)
+	assert.NoError(t, err)
+
+	s := newTestService(t, emptyRepository{}, validation.SimpleValidator{})
+	s.certificates, err = newCertificateReloader(certFile, keyFile, caFile, s.logger)
+	assert.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	signals := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- s.serve(listener, signals)
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.certificate)
+	get := func(certificates []tls.Certificate) (*http.Response, error) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certificates}, ForceAttemptHTTP2: true}}
+		return client.Get("https://" + listener.Addr().String() + "/api/metadata/8a4d6b7e-4f5c-4b8e-9d3a-2f1e0c9b8a7d")
+	}
+
+	res, err := get([]tls.Certificate{clientCertificate})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, "first", res.TLS.PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, "h2", res.TLS.NegotiatedProtocol)
+	res.Body.Close()
+
+	// clients without a certificate signed by the client CAs are rejected
+	_, err = get(nil)
+	assert.Error(t, err)
+
+	// renewed certificates are picked up once both the certificate and its key changed
+	cert, key = ca.issue(t, 4, "second", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert, time.Now())
+	reloaded, err := s.certificates.reload()
+	assert.Error(t, err)
+	assert.False(t, reloaded)
+	writeFile(t, keyFile, key, time.Now())
+	reloaded, err = s.certificates.reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	reloaded, err = s.certificates.reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	res, err = get([]tls.Certificate{clientCertificate})
+	assert.NoError(t, err)
+	assert.Equal(t, "second", res.TLS.PeerCertificates[0].Subject.CommonName)
+	res.Body.Close()
+
+	signals <- syscall.SIGTERM
+	assert.NoError(t, <-stopped)
+}
+
+func Test_TLSOptions(t *testing.T) {
+	_, err := newCertificateReloader("cert.pem", "", "", nil)
+	assert.EqualError(t, err, "the TLS certificate and key must be set together")
+	_, err = newCertificateReloader("", "", "ca.pem", nil)
+	assert.Error(t, err)
+}
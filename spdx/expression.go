@@ -0,0 +1,333 @@
+package spdx
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// identifier is a license or exception ID of the SPDX license list
+type identifier struct {
+	id         string
+	deprecated bool
+}
+
+// aliases maps common spellings of licenses that are not SPDX IDs, in the form returned by normalize,
+// to the canonical ID. Ambiguous names such as BSD or GPL are deliberately left out
+var aliases = map[string]string{
+	"apache2":                "Apache-2.0",
+	"apachev2":               "Apache-2.0",
+	"asl2":                   "Apache-2.0",
+	"asl20":                  "Apache-2.0",
+	"apachelicense2":         "Apache-2.0",
+	"apachelicense20":        "Apache-2.0",
+	"apachelicenseversion20": "Apache-2.0",
+	"apachesoftwarelicense2": "Apache-2.0",
+	"mitlicense":             "MIT",
+	"expat":                  "MIT",
+	"isclicense":             "ISC",
+	"bsd2clause":             "BSD-2-Clause",
+	"bsd3clause":             "BSD-3-Clause",
+	"simplifiedbsd":          "BSD-2-Clause",
+	"newbsd":                 "BSD-3-Clause",
+	"gplv2":                  "GPL-2.0-only",
+	"gplv2+":                 "GPL-2.0-or-later",
+	"gplv3":                  "GPL-3.0-only",
+	"gplv3+":                 "GPL-3.0-or-later",
+	"lgplv21":                "LGPL-2.1-only",
+	"lgplv21+":               "LGPL-2.1-or-later",
+	"lgplv3":                 "LGPL-3.0-only",
+	"lgplv3+":                "LGPL-3.0-or-later",
+	"agplv3":                 "AGPL-3.0-only",
+	"agplv3+":                "AGPL-3.0-or-later",
+	"mpl2":                   "MPL-2.0",
+	"epl1":                   "EPL-1.0",
+	"epl2":                   "EPL-2.0",
+	"publicdomaincc0":        "CC0-1.0",
+	"cc0":                    "CC0-1.0",
+}
+
+// normalizedLicenses maps license IDs in the form returned by normalize to their canonical ID, so that
+// variants like "apache 2.0" are recognized. Forms shared by several IDs are left out
+var normalizedLicenses = func() map[string]string {
+	result := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for _, license := range licenses {
+		key := normalize(license.id)
+		if existing, k := result[key]; k && existing != license.id {
+			ambiguous[key] = true
+		}
+		result[key] = license.id
+	}
+	for key := range ambiguous {
+		delete(result, key)
+	}
+	return result
+}()
+
+// normalize lowercases s and drops everything but letters, digits and +
+func normalize(s string) string {
+	sb := strings.Builder{}
+	for _, c := range strings.ToLower(s) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '+' {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// Canonicalize returns the canonical form of a SPDX license ID or license expression, e.g. "mit or apache 2"
+// becomes "MIT OR Apache-2.0". IDs are matched case-insensitively, common aliases are replaced by the
+// corresponding ID and deprecated GNU IDs by their -only or -or-later replacement
+func Canonicalize(license string) (string, error) {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return "", fmt.Errorf("empty license")
+	}
+	if id, k := lookupLicense(license); k {
+		return id, nil
+	}
+	tokens := tokenize(license)
+	p := parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if t := p.peek(); t.value != "" {
+		return "", fmt.Errorf("unexpected %s at position %d", t.value, t.position)
+	}
+	return expr.String(), nil
+}
+
+// lookupLicense returns the canonical form of a single license
+func lookupLicense(s string) (string, bool) {
+	if ref, k := licenseRef(s); k {
+		return ref, true
+	}
+	if license, k := licenses[strings.ToLower(s)]; k {
+		return replaceDeprecated(license), true
+	}
+	key := normalize(s)
+	if id, k := aliases[key]; k {
+		return id, true
+	}
+	if id, k := normalizedLicenses[key]; k {
+		return replaceDeprecated(licenses[strings.ToLower(id)]), true
+	}
+	return "", false
+}
+
+// replaceDeprecated replaces deprecated IDs such as GPL-2.0 and GPL-2.0+ by GPL-2.0-only and GPL-2.0-or-later
+func replaceDeprecated(license identifier) string {
+	if !license.deprecated {
+		return license.id
+	}
+	if strings.HasSuffix(license.id, "+") {
+		if replacement, k := licenses[strings.ToLower(strings.TrimSuffix(license.id, "+")+"-or-later")]; k {
+			return replacement.id
+		}
+	} else if replacement, k := licenses[strings.ToLower(license.id+"-only")]; k {
+		return replacement.id
+	}
+	return license.id
+}
+
+// licenseRef validates user defined licenses of the form LicenseRef-id or DocumentRef-id:LicenseRef-id
+func licenseRef(s string) (string, bool) {
+	document := ""
+	if strings.HasPrefix(strings.ToLower(s), "documentref-") {
+		i := strings.Index(s, ":")
+		if i < 0 || !isIDString(s[len("documentref-"):i]) {
+			return "", false
+		}
+		document = "DocumentRef-" + s[len("documentref-"):i] + ":"
+		s = s[i+1:]
+	}
+	if !strings.HasPrefix(strings.ToLower(s), "licenseref-") || !isIDString(s[len("licenseref-"):]) {
+		return "", false
+	}
+	return document + "LicenseRef-" + s[len("licenseref-"):], true
+}
+
+func isIDString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// expression is a node of a parsed license expression
+type expression interface {
+	String() string
+}
+
+// simpleExpression is a single license, optionally followed by + and an exception
+type simpleExpression struct {
+	license   string
+	orLater   bool
+	exception string
+}
+
+// compoundExpression joins two expressions with AND or OR
+type compoundExpression struct {
+	operator string
+	left     expression
+	right    expression
+}
+
+func (e simpleExpression) String() string {
+	s := e.license
+	if e.orLater {
+		s += "+"
+	}
+	if e.exception != "" {
+		s += " WITH " + e.exception
+	}
+	return s
+}
+
+func (e compoundExpression) String() string {
+	return e.operand(e.left) + " " + e.operator + " " + e.operand(e.right)
+}
+
+// operand parenthesizes OR expressions nested in AND expressions, as AND binds tighter
+func (e compoundExpression) operand(operand expression) string {
+	if c, k := operand.(compoundExpression); k && e.operator == "AND" && c.operator == "OR" {
+		return "(" + c.String() + ")"
+	}
+	return operand.String()
+}
+
+type token struct {
+	value    string
+	position int
+}
+
+func tokenize(s string) []token {
+	runes := []rune(s)
+	tokens := make([]token, 0)
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '(' || runes[i] == ')':
+			tokens = append(tokens, token{value: string(runes[i]), position: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				i++
+			}
+			tokens = append(tokens, token{value: string(runes[start:i]), position: start + 1})
+		}
+	}
+	return tokens
+}
+
+// parser is a recursive descent parser of license expressions. WITH binds tighter than AND,
+// which binds tighter than OR
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	end := 1
+	if len(p.tokens) > 0 {
+		last := p.tokens[len(p.tokens)-1]
+		end = last.position + len([]rune(last.value))
+	}
+	return token{position: end}
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func isOperator(t token, operator string) bool {
+	return strings.EqualFold(t.value, operator)
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isOperator(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = compoundExpression{operator: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for isOperator(p.peek(), "AND") {
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = compoundExpression{operator: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	t := p.next()
+	switch {
+	case t.value == "":
+		return nil, fmt.Errorf("expected a license at position %d", t.position)
+	case t.value == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.value != ")" {
+			return nil, fmt.Errorf("expected ) at position %d", closing.position)
+		}
+		return expr, nil
+	case t.value == ")" || isOperator(t, "AND") || isOperator(t, "OR") || isOperator(t, "WITH"):
+		return nil, fmt.Errorf("expected a license at position %d but got %s", t.position, t.value)
+	}
+	simple := simpleExpression{}
+	if id, k := lookupLicense(t.value); k {
+		simple.license = id
+	} else if id, k := lookupLicense(strings.TrimSuffix(t.value, "+")); k && strings.HasSuffix(t.value, "+") {
+		simple.license, simple.orLater = id, true
+	} else {
+		return nil, fmt.Errorf("unknown license ID %s at position %d", t.value, t.position)
+	}
+	if isOperator(p.peek(), "WITH") {
+		p.next()
+		e := p.next()
+		exception, k := exceptions[strings.ToLower(e.value)]
+		if e.value == "" {
+			return nil, fmt.Errorf("expected an exception at position %d", e.position)
+		}
+		if !k {
+			return nil, fmt.Errorf("unknown exception ID %s at position %d", e.value, e.position)
+		}
+		simple.exception = exception.id
+	}
+	return simple, nil
+}
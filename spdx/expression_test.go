@@ -0,0 +1,51 @@
+package spdx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Canonicalize(t *testing.T) {
+	cases := map[string]string{
+		"Apache-2.0":                           "Apache-2.0",
+		"apache-2.0":                           "Apache-2.0",
+		"apache 2":                             "Apache-2.0",
+		"Apache 2.0":                           "Apache-2.0",
+		"ASL2":                                 "Apache-2.0",
+		"Apache License, Version 2.0":          "Apache-2.0",
+		"mit":                                  "MIT",
+		"GPL-2.0":                              "GPL-2.0-only",
+		"GPL-3.0+":                             "GPL-3.0-or-later",
+		"GPLv3":                                "GPL-3.0-only",
+		"mit or apache-2.0":                    "MIT OR Apache-2.0",
+		"MIT OR (Apache-2.0 AND BSD-3-Clause)": "MIT OR Apache-2.0 AND BSD-3-Clause",
+		"(MIT OR Apache-2.0) AND ISC":          "(MIT OR Apache-2.0) AND ISC",
+		"gpl-2.0 with classpath-exception-2.0": "GPL-2.0-only WITH Classpath-exception-2.0",
+		"Apache-1.1+":                          "Apache-1.1+",
+		"licenseref-internal.1":                "LicenseRef-internal.1",
+		"DocumentRef-spdx:LicenseRef-x":        "DocumentRef-spdx:LicenseRef-x",
+	}
+	for license, expected := range cases {
+		canonical, err := Canonicalize(license)
+		assert.NoError(t, err, license)
+		assert.Equal(t, expected, canonical, license)
+	}
+}
+
+func Test_CanonicalizeRejectsInvalidExpressions(t *testing.T) {
+	cases := map[string]string{
+		"":                       "empty license",
+		"GNU":                    "unknown license ID GNU at position 1",
+		"MIT OR":                 "expected a license at position 7",
+		"MIT AND OR ISC":         "expected a license at position 9 but got OR",
+		"(MIT OR ISC":            "expected ) at position 12",
+		"MIT ISC":                "unexpected ISC at position 5",
+		"MIT WITH foo-exception": "unknown exception ID foo-exception at position 10",
+		"LicenseRef-":            "unknown license ID LicenseRef- at position 1",
+	}
+	for license, message := range cases {
+		_, err := Canonicalize(license)
+		assert.EqualError(t, err, message, license)
+	}
+}
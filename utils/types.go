@@ -33,6 +33,27 @@ func (s *Set) Intersect(other Set) *Set {
 	return intersection
 }
 
+func (s *Set) Union(other Set) *Set {
+	union := NewSet()
+	for v := range *s {
+		union.Add(v)
+	}
+	for v := range other {
+		union.Add(v)
+	}
+	return union
+}
+
+func (s *Set) Difference(other Set) *Set {
+	difference := NewSet()
+	for v := range *s {
+		if !other.Has(v) {
+			difference.Add(v)
+		}
+	}
+	return difference
+}
+
 func (s Set) ToSlice() []interface{} {
 	slice := make([]interface{}, 0)
 	for k, _ := range s {
@@ -0,0 +1,283 @@
+package validation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/semver"
+	"gitlab.com/erikwu09/yamlr/spdx"
+	"gopkg.in/yaml.v2"
+)
+
+// Formats a field can be checked against. Values matching the format are replaced by their canonical form
+const (
+	FormatURL    = "url"
+	FormatEmail  = "email"
+	FormatSemver = "semver"
+	FormatSPDX   = "spdx"
+)
+
+// Rule is a validation policy on a field. Every check that is set has to pass; checks other than required
+// are skipped for empty values
+type Rule struct {
+	ID    string `yaml:"id"`
+	Field string `yaml:"field"`
+	// Message replaces the reason of the errors reported by the rule
+	Message string `yaml:"message"`
+	// Severity is error (the default) or warning. Warnings do not prevent a write
+	Severity      string   `yaml:"severity"`
+	Required      bool     `yaml:"required"`
+	Format        string   `yaml:"format"`
+	MinLength     *int     `yaml:"minLength"`
+	MaxLength     *int     `yaml:"maxLength"`
+	Pattern       string   `yaml:"pattern"`
+	AllowedValues []string `yaml:"allowedValues"`
+	// Schemes lists the allowed URL schemes
+	Schemes []string `yaml:"schemes"`
+	// Domains lists the allowed email domains, subdomains included
+	Domains []string `yaml:"domains"`
+
+	pattern *regexp.Regexp
+}
+
+// RuleSet is the content of a rules file
+type RuleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// RuleValidator validates metadata against a set of declarative rules
+type RuleValidator struct {
+	rules []Rule
+}
+
+// fieldValue is a value of a field of a metadata along with its JSON pointer
+type fieldValue struct {
+	path  string
+	value *string
+}
+
+// fields maps the field names accepted in rules to the values they designate
+var fields = map[string]func(metadata *models.Metadata) []fieldValue{
+	"title":             scalarField("title", func(m *models.Metadata) *string { return &m.Title }),
+	"version":           scalarField("version", func(m *models.Metadata) *string { return &m.Version }),
+	"company":           scalarField("company", func(m *models.Metadata) *string { return &m.Company }),
+	"website":           scalarField("website", func(m *models.Metadata) *string { return &m.Website }),
+	"source":            scalarField("source", func(m *models.Metadata) *string { return &m.Source }),
+	"license":           scalarField("license", func(m *models.Metadata) *string { return &m.License }),
+	"description":       scalarField("description", func(m *models.Metadata) *string { return &m.Description }),
+	"maintainers.name":  maintainerField("name", func(m *models.Maintainer) *string { return &m.Name }),
+	"maintainers.email": maintainerField("email", func(m *models.Maintainer) *string { return &m.Email }),
+}
+
+func scalarField(key string, get func(*models.Metadata) *string) func(*models.Metadata) []fieldValue {
+	return func(metadata *models.Metadata) []fieldValue {
+		return []fieldValue{{path: app.Pointer(key), value: get(metadata)}}
+	}
+}
+
+func maintainerField(key string, get func(*models.Maintainer) *string) func(*models.Metadata) []fieldValue {
+	return func(metadata *models.Metadata) []fieldValue {
+		values := make([]fieldValue, 0, len(metadata.Maintainers))
+		for i, m := range metadata.Maintainers {
+			if m != nil {
+				values = append(values, fieldValue{path: app.Pointer("maintainers", i, key), value: get(m)})
+			}
+		}
+		return values
+	}
+}
+
+// LoadRuleValidator reads the rules file at path
+func LoadRuleValidator(path string) (*RuleValidator, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewRuleValidator(content)
+}
+
+// NewRuleValidator parses the rules of a rules file and checks that they are well formed
+func NewRuleValidator(content []byte) (*RuleValidator, error) {
+	ruleSet := RuleSet{}
+	if err := yaml.UnmarshalStrict(content, &ruleSet); err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool)
+	for i := range ruleSet.Rules {
+		rule := &ruleSet.Rules[i]
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule %d has no id", i+1)
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("rule %s is defined more than once", rule.ID)
+		}
+		ids[rule.ID] = true
+		rule.Field = strings.ToLower(rule.Field)
+		if _, k := fields[rule.Field]; !k && rule.Field != "maintainers" {
+			return nil, fmt.Errorf("rule %s: unknown field %s", rule.ID, rule.Field)
+		}
+		if rule.Field == "maintainers" && (rule.Format != "" || rule.MinLength != nil || rule.MaxLength != nil || rule.Pattern != "" ||
+			len(rule.AllowedValues) > 0 || len(rule.Schemes) > 0 || len(rule.Domains) > 0) {
+			return nil, fmt.Errorf("rule %s: maintainers only support required", rule.ID)
+		}
+		switch rule.Severity {
+		case "", app.SeverityError, app.SeverityWarning:
+		default:
+			return nil, fmt.Errorf("rule %s: unknown severity %s", rule.ID, rule.Severity)
+		}
+		switch rule.Format {
+		case "", FormatURL, FormatEmail, FormatSemver, FormatSPDX:
+		default:
+			return nil, fmt.Errorf("rule %s: unknown format %s", rule.ID, rule.Format)
+		}
+		if rule.Pattern != "" {
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %s", rule.ID, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+	return &RuleValidator{rules: ruleSet.Rules}, nil
+}
+
+func (v RuleValidator) ValidateAndSanitize(metadata *models.Metadata) error {
+	aggregateErr := app.NewAggregatedValidationError()
+	for _, rule := range v.rules {
+		if rule.Field == "maintainers" {
+			if rule.Required && len(metadata.Maintainers) == 0 {
+				aggregateErr.AddError(rule.error(app.Pointer("maintainers"), "empty value"))
+			}
+			continue
+		}
+		for _, field := range fields[rule.Field](metadata) {
+			if reason := rule.check(field.value); reason != "" {
+				aggregateErr.AddError(rule.error(field.path, reason))
+			}
+		}
+	}
+	if len(aggregateErr.Errors()) == 0 {
+		return nil
+	}
+	return aggregateErr
+}
+
+// SanitizeURLs normalizes the URLs and emails of a search the same way they are normalized on write
+func (v RuleValidator) SanitizeURLs(metadata *models.Metadata) error {
+	return SimpleValidator{}.SanitizeURLs(metadata)
+}
+
+func (rule Rule) error(path string, reason string) app.ValidationError {
+	if rule.Message != "" {
+		reason = rule.Message
+	}
+	return app.ValidationError{Path: path, Reason: reason, Rule: rule.ID, Severity: rule.Severity}
+}
+
+// check returns why the value breaks the rule, or an empty string if it does not.
+// Values matching the format of the rule are replaced by their canonical form
+func (rule Rule) check(value *string) string {
+	if *value == "" {
+		if rule.Required {
+			return "empty value"
+		}
+		return ""
+	}
+	if rule.Format != "" {
+		canonical, reason := checkFormat(rule.Format, *value)
+		if reason != "" {
+			return reason
+		}
+		*value = canonical
+	}
+	length := len([]rune(*value))
+	if rule.MinLength != nil && length < *rule.MinLength {
+		return fmt.Sprintf("shorter than %d characters", *rule.MinLength)
+	}
+	if rule.MaxLength != nil && length > *rule.MaxLength {
+		return fmt.Sprintf("longer than %d characters", *rule.MaxLength)
+	}
+	if rule.pattern != nil && !rule.pattern.MatchString(*value) {
+		return fmt.Sprintf("does not match %s", rule.Pattern)
+	}
+	if len(rule.AllowedValues) > 0 && !contains(rule.AllowedValues, *value) {
+		return fmt.Sprintf("not one of %s", strings.Join(rule.AllowedValues, ", "))
+	}
+	if len(rule.Schemes) > 0 {
+		u, err := url.Parse(*value)
+		if err != nil {
+			return "url not properly formed"
+		}
+		if !contains(rule.Schemes, strings.ToLower(u.Scheme)) {
+			return fmt.Sprintf("url scheme is not one of %s", strings.Join(rule.Schemes, ", "))
+		}
+	}
+	if len(rule.Domains) > 0 {
+		address, err := mail.ParseAddress(*value)
+		if err != nil {
+			return "email address not properly formed"
+		}
+		if !inDomains(address.Address, rule.Domains) {
+			return fmt.Sprintf("email domain is not one of %s", strings.Join(rule.Domains, ", "))
+		}
+	}
+	return ""
+}
+
+// checkFormat returns the canonical form of a value, or why it does not match the format
+func checkFormat(format string, value string) (string, string) {
+	switch format {
+	case FormatURL:
+		u, err := url.Parse(value)
+		if err != nil {
+			return "", "url not properly formed"
+		}
+		return u.String(), ""
+	case FormatEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil {
+			return "", "email address not properly formed"
+		}
+		return normalizeEmail(address), ""
+	case FormatSemver:
+		version, err := semver.Parse(value)
+		if err != nil {
+			return "", "not a semantic version: " + err.Error()
+		}
+		return version.String(), ""
+	case FormatSPDX:
+		license, err := spdx.Canonicalize(value)
+		if err != nil {
+			return "", "not a SPDX license expression: " + err.Error()
+		}
+		return license, ""
+	default:
+		return value, ""
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func inDomains(address string, domains []string) bool {
+	domain := strings.ToLower(address[strings.LastIndex(address, "@")+1:])
+	for _, d := range domains {
+		d = strings.ToLower(d)
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
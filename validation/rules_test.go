@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/erikwu09/yamlr/app"
+	"gitlab.com/erikwu09/yamlr/models"
+)
+
+func validMetadata() *models.Metadata {
+	return &models.Metadata{
+		Title:       "dummyTitle",
+		Version:     "v1.0",
+		Maintainers: []*models.Maintainer{&models.Maintainer{Name: "Tester", Email: "erikwu@microsoft.com"}},
+		Company:     "dummyCompany",
+		Website:     "https://www.dummyCompany.com",
+		Source:      "https://github.com/dummyCompany",
+		License:     "apache 2",
+		Description: "Lorem ipsum dolor sit amet",
+	}
+}
+
+func Test_DefaultRulesMatchBuiltInValidation(t *testing.T) {
+	testee, err := LoadRuleValidator("../rules.yaml")
+	assert.NoError(t, err)
+
+	metadata := validMetadata()
+	assert.NoError(t, testee.ValidateAndSanitize(metadata))
+	assert.Equal(t, "1.0.0", metadata.Version)
+	assert.Equal(t, "Apache-2.0", metadata.License)
+	assert.Equal(t, "erikwu@microsoft.com", metadata.Maintainers[0].Email)
+
+	metadata = validMetadata()
+	metadata.Title = ""
+	metadata.Maintainers[0].Email = "emicrosoft.com"
+	err = testee.ValidateAndSanitize(metadata)
+	aggregatedErr, k := err.(app.AggregatedValidationError)
+	assert.True(t, k)
+	assert.Equal(t, []app.ValidationError{
+		{Path: "/title", Reason: "empty value", Rule: "title-required"},
+		{Path: "/maintainers/0/email", Reason: "email address not properly formed", Rule: "maintainer-email"},
+	}, aggregatedErr.Errors())
+}
+
+func Test_RuleChecks(t *testing.T) {
+	testee, err := NewRuleValidator([]byte(`
+rules:
+  - id: title-length
+    field: title
+    minLength: 3
+    maxLength: 12
+  - id: company-allowed
+    field: company
+    allowedValues: [Microsoft, Amazon]
+    message: company is not onboarded
+  - id: version-pattern
+    field: version
+    pattern: '^[0-9]+\.[0-9]+\.[0-9]+$'
+  - id: website-https
+    field: website
+    schemes: [https]
+  - id: maintainer-domain
+    field: maintainers.email
+    domains: [microsoft.com]
+`))
+	assert.NoError(t, err)
+
+	metadata := validMetadata()
+	metadata.Company = "Microsoft"
+	metadata.Version = "1.0.0"
+	metadata.Maintainers = append(metadata.Maintainers, &models.Maintainer{Name: "Jane", Email: "jane@azure.microsoft.com"})
+	assert.NoError(t, testee.ValidateAndSanitize(metadata))
+
+	metadata = validMetadata()
+	metadata.Title = "Azure Kubernetes Service"
+	metadata.Website = "http://azure.com"
+	metadata.Maintainers[0].Email = "jane@amazon.com"
+	err = testee.ValidateAndSanitize(metadata)
+	aggregatedErr, k := err.(app.AggregatedValidationError)
+	assert.True(t, k)
+	assert.Equal(t, []app.ValidationError{
+		{Path: "/title", Reason: "longer than 12 characters", Rule: "title-length"},
+		{Path: "/company", Reason: "company is not onboarded", Rule: "company-allowed"},
+		{Path: "/version", Reason: `does not match ^[0-9]+\.[0-9]+\.[0-9]+$`, Rule: "version-pattern"},
+		{Path: "/website", Reason: "url scheme is not one of https", Rule: "website-https"},
+		{Path: "/maintainers/0/email", Reason: "email domain is not one of microsoft.com", Rule: "maintainer-domain"},
+	}, aggregatedErr.Errors())
+}
+
+func Test_WarningRules(t *testing.T) {
+	testee, err := LoadRuleValidator("../rules.yaml")
+	assert.NoError(t, err)
+
+	metadata := validMetadata()
+	metadata.Website = "http://www.dummyCompany.com"
+	metadata.Description = "Lorem ipsum"
+	err = testee.ValidateAndSanitize(metadata)
+	aggregatedErr, k := err.(app.AggregatedValidationError)
+	assert.True(t, k)
+	assert.False(t, aggregatedErr.HasErrors())
+	assert.Equal(t, []app.ValidationError{
+		{Path: "/website", Reason: "url scheme is not one of https", Rule: "website-https", Severity: app.SeverityWarning},
+		{Path: "/description", Reason: "descriptions should be at least 20 characters long", Rule: "description-length", Severity: app.SeverityWarning},
+	}, aggregatedErr.Warnings())
+}
+
+func Test_RejectsMalformedRules(t *testing.T) {
+	cases := map[string]string{
+		"rules:\n  - field: title\n    required: true":                     "rule 1 has no id",
+		"rules:\n  - id: a\n    field: colour":                             "rule a: unknown field colour",
+		"rules:\n  - id: a\n    field: title\n  - id: a\n    field: title": "rule a is defined more than once",
+		"rules:\n  - id: a\n    field: title\n    pattern: '('":            "rule a: error parsing regexp: missing closing ): `(`",
+		"rules:\n  - id: a\n    field: title\n    format: xml":             "rule a: unknown format xml",
+		"rules:\n  - id: a\n    field: maintainers\n    minLength: 1":      "rule a: maintainers only support required",
+		"rules:\n  - id: a\n    field: title\n    severity: info":          "rule a: unknown severity info",
+	}
+	for content, message := range cases {
+		_, err := NewRuleValidator([]byte(content))
+		assert.EqualError(t, err, message, content)
+	}
+	_, err := NewRuleValidator([]byte("rules:\n  - id: a\n    field: title\n    requird: true"))
+	assert.Error(t, err)
+}
@@ -4,9 +4,12 @@ import (
 	"net/mail"
 	"net/url"
 	_ "reflect"
+	"strings"
 
 	"gitlab.com/erikwu09/yamlr/app"
 	"gitlab.com/erikwu09/yamlr/models"
+	"gitlab.com/erikwu09/yamlr/semver"
+	"gitlab.com/erikwu09/yamlr/spdx"
 )
 
 // Simple, brute-force validation
@@ -18,7 +21,7 @@ func (v SimpleValidator) SanitizeURLs(metadata *models.Metadata) error {
 	if metadata.Website != "" {
 		websiteURL, err := url.Parse(metadata.Website)
 		if err != nil {
-			aggregateErr.AddError(app.ValidationError{Path: "Website", Reason: "url not properly formed"})
+			aggregateErr.AddError(app.ValidationError{Path: app.Pointer("website"), Reason: "url not properly formed"})
 		} else {
 			metadata.Website = websiteURL.String()
 		}
@@ -26,18 +29,22 @@ func (v SimpleValidator) SanitizeURLs(metadata *models.Metadata) error {
 	if metadata.Source != "" {
 		sourceURL, err := url.Parse(metadata.Source)
 		if err != nil {
-			aggregateErr.AddError(app.ValidationError{Path: "Source", Reason: "url not properly formed"})
+			aggregateErr.AddError(app.ValidationError{Path: app.Pointer("source"), Reason: "url not properly formed"})
 		} else {
 			metadata.Source = sourceURL.String()
 		}
 	}
 	if metadata.Maintainers != nil {
-		for _, m := range metadata.Maintainers {
+		for i, m := range metadata.Maintainers {
+			// maintainers searched by name only have no email to normalize
+			if m == nil || m.Email == "" {
+				continue
+			}
 			email, err := mail.ParseAddress(m.Email)
 			if err != nil {
-				aggregateErr.AddError(app.ValidationError{Path: "Maintainers[].Email", Reason: "email address not properly formed"})
+				aggregateErr.AddError(app.ValidationError{Path: app.Pointer("maintainers", i, "email"), Reason: "email address not properly formed"})
 			} else {
-				m.Email = email.String()
+				m.Email = normalizeEmail(email)
 			}
 		}
 	}
@@ -50,57 +57,75 @@ func (v SimpleValidator) SanitizeURLs(metadata *models.Metadata) error {
 func (v SimpleValidator) ValidateAndSanitize(metadata *models.Metadata) error {
 	aggregateErr := app.NewAggregatedValidationError()
 	if metadata.Title == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "Title", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("title"), Reason: "empty value"})
 	}
-	// TODO: check for version semantics
 	if metadata.Version == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "Version", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("version"), Reason: "empty value"})
+	} else {
+		// versions are stored in their canonical form, i.e. v1.2 is stored as 1.2.0
+		version, err := semver.Parse(metadata.Version)
+		if err != nil {
+			aggregateErr.AddError(app.ValidationError{Path: app.Pointer("version"), Reason: "not a semantic version: " + err.Error()})
+		} else {
+			metadata.Version = version.String()
+		}
 	}
 	if metadata.Company == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "Company", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("company"), Reason: "empty value"})
 	}
 	if metadata.Website == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "Website", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("website"), Reason: "empty value"})
 	} else {
 		websiteURL, err := url.Parse(metadata.Website)
 		if err != nil {
-			aggregateErr.AddError(app.ValidationError{Path: "Website", Reason: "url not properly formed"})
+			aggregateErr.AddError(app.ValidationError{Path: app.Pointer("website"), Reason: "url not properly formed"})
 		} else {
 			metadata.Website = websiteURL.String()
 		}
 	}
 	if metadata.Source == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "Source", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("source"), Reason: "empty value"})
 	} else {
 		sourceURL, err := url.Parse(metadata.Source)
 		if err != nil {
-			aggregateErr.AddError(app.ValidationError{Path: "Source", Reason: "url not properly formed"})
+			aggregateErr.AddError(app.ValidationError{Path: app.Pointer("source"), Reason: "url not properly formed"})
 		} else {
 			metadata.Source = sourceURL.String()
 		}
 	}
 	if metadata.License == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "License", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("license"), Reason: "empty value"})
+	} else {
+		license, err := spdx.Canonicalize(metadata.License)
+		if err != nil {
+			aggregateErr.AddError(app.ValidationError{Path: app.Pointer("license"), Reason: "not a SPDX license expression: " + err.Error()})
+		} else {
+			metadata.License = license
+		}
 	}
 	if metadata.Description == "" {
-		aggregateErr.AddError(app.ValidationError{Path: "Description", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("description"), Reason: "empty value"})
 	}
 	if metadata.Maintainers == nil || len(metadata.Maintainers) == 0 {
-		aggregateErr.AddError(app.ValidationError{Path: "Maintainers", Reason: "empty value"})
+		aggregateErr.AddError(app.ValidationError{Path: app.Pointer("maintainers"), Reason: "empty value"})
 	} else {
-		for _, m := range metadata.Maintainers {
+		for i, m := range metadata.Maintainers {
+			if m == nil {
+				aggregateErr.AddError(app.ValidationError{Path: app.Pointer("maintainers", i), Reason: "empty value"})
+				continue
+			}
 			if m.Email == "" {
-				aggregateErr.AddError(app.ValidationError{Path: "Maintainers[].Email", Reason: "empty value"})
+				aggregateErr.AddError(app.ValidationError{Path: app.Pointer("maintainers", i, "email"), Reason: "empty value"})
 			} else {
 				email, err := mail.ParseAddress(m.Email)
 				if err != nil {
-					aggregateErr.AddError(app.ValidationError{Path: "Maintainers[].Email", Reason: "email address not properly formed"})
+					aggregateErr.AddError(app.ValidationError{Path: app.Pointer("maintainers", i, "email"), Reason: "email address not properly formed"})
 				} else {
-					m.Email = email.String()
+					m.Email = normalizeEmail(email)
 				}
 			}
 			if m.Name == "" {
-				aggregateErr.AddError(app.ValidationError{Path: "Maintainers[].Email", Reason: "empty value"})
+				aggregateErr.AddError(app.ValidationError{Path: app.Pointer("maintainers", i, "name"), Reason: "empty value"})
 			}
 		}
 	}
@@ -109,3 +134,9 @@ func (v SimpleValidator) ValidateAndSanitize(metadata *models.Metadata) error {
 	}
 	return aggregateErr
 }
+
+// normalizeEmail returns the bare lowercased address of an email, e.g. erik.wu@microsoft.com for
+// "Erik Wu <Erik.Wu@microsoft.com>", which is how emails are stored and searched
+func normalizeEmail(email *mail.Address) string {
+	return strings.ToLower(email.Address)
+}
@@ -18,7 +18,7 @@ func Test_ValidatesCorrectData(t *testing.T) {
 		Company:     "dummyCompany",
 		Website:     "http://www.dummyCompany.com",
 		Source:      "github.com/dummyCompany",
-		License:     "GNU",
+		License:     "GPL-3.0-only",
 		Description: "Lorem ipsum",
 	}
 	testee := SimpleValidator{}
@@ -37,7 +37,7 @@ func Test_InvalidatesBadData(t *testing.T) {
 		Company:     "dummyCompany",
 		Website:     "http://www.dummyCompany.com",
 		Source:      "dummyCompany",
-		License:     "GNU",
+		License:     "GPL-3.0-only",
 		Description: "Lorem ipsum",
 	}
 	testee := SimpleValidator{}
@@ -48,3 +48,63 @@ func Test_InvalidatesBadData(t *testing.T) {
 	assert.True(t, len(aggregatedErr.Errors()) == 2)
 	log.Println(err.Error())
 }
+
+func Test_NormalizesVersion(t *testing.T) {
+	cases := map[string]string{
+		"1.0":                  "1.0.0",
+		"v2.1.3":               "2.1.3",
+		" V3 ":                 "3.0.0",
+		"1.0.0-rc.1+build.007": "1.0.0-rc.1+build.007",
+	}
+	for version, expected := range cases {
+		metadata := &models.Metadata{Title: "dummyTitle", Version: version, Company: "dummyCompany", Website: "dummyCompany.com",
+			Source: "github.com/dummyCompany", License: "GPL-3.0-only", Description: "Lorem ipsum",
+			Maintainers: []*models.Maintainer{&models.Maintainer{Name: "Tester", Email: "erikwu@microsoft.com"}}}
+		assert.NoError(t, SimpleValidator{}.ValidateAndSanitize(metadata), version)
+		assert.Equal(t, expected, metadata.Version)
+	}
+}
+
+func Test_InvalidatesMalformedVersion(t *testing.T) {
+	cases := map[string]string{
+		"1.02.0":        "not a semantic version: minor version 02 has a leading zero",
+		"1.2.3.4":       "not a semantic version: 1.2.3.4 has more than major, minor and patch versions",
+		"1.x":           "not a semantic version: minor version x is not a number",
+		"1.2.3-rc.01":   "not a semantic version: pre-release identifier 01 has a leading zero",
+		"1.2.3-":        "not a semantic version: pre-release has an empty identifier",
+		"1.2.3+build_1": "not a semantic version: build metadata identifier build_1 contains '_', only [0-9A-Za-z-] are allowed",
+		"latest":        "not a semantic version: major version latest is not a number",
+	}
+	for version, reason := range cases {
+		metadata := &models.Metadata{Title: "dummyTitle", Version: version, Company: "dummyCompany", Website: "dummyCompany.com",
+			Source: "github.com/dummyCompany", License: "GPL-3.0-only", Description: "Lorem ipsum",
+			Maintainers: []*models.Maintainer{&models.Maintainer{Name: "Tester", Email: "erikwu@microsoft.com"}}}
+		err := SimpleValidator{}.ValidateAndSanitize(metadata)
+		aggregatedErr, k := err.(app.AggregatedValidationError)
+		assert.True(t, k, version)
+		assert.Equal(t, []app.ValidationError{{Path: "/version", Reason: reason}}, aggregatedErr.Errors())
+	}
+}
+
+func Test_CanonicalizesLicense(t *testing.T) {
+	cases := map[string]string{
+		"apache 2":                             "Apache-2.0",
+		"mit OR asl2":                          "MIT OR Apache-2.0",
+		"GPL-2.0 WITH Classpath-exception-2.0": "GPL-2.0-only WITH Classpath-exception-2.0",
+	}
+	for license, expected := range cases {
+		metadata := &models.Metadata{Title: "dummyTitle", Version: "1.0.0", Company: "dummyCompany", Website: "dummyCompany.com",
+			Source: "github.com/dummyCompany", License: license, Description: "Lorem ipsum",
+			Maintainers: []*models.Maintainer{&models.Maintainer{Name: "Tester", Email: "erikwu@microsoft.com"}}}
+		assert.NoError(t, SimpleValidator{}.ValidateAndSanitize(metadata), license)
+		assert.Equal(t, expected, metadata.License)
+	}
+
+	metadata := &models.Metadata{Title: "dummyTitle", Version: "1.0.0", Company: "dummyCompany", Website: "dummyCompany.com",
+		Source: "github.com/dummyCompany", License: "MIT OR Proprietary", Description: "Lorem ipsum",
+		Maintainers: []*models.Maintainer{&models.Maintainer{Name: "Tester", Email: "erikwu@microsoft.com"}}}
+	err := SimpleValidator{}.ValidateAndSanitize(metadata)
+	aggregatedErr, k := err.(app.AggregatedValidationError)
+	assert.True(t, k)
+	assert.Equal(t, []app.ValidationError{{Path: "/license", Reason: "not a SPDX license expression: unknown license ID Proprietary at position 8"}}, aggregatedErr.Errors())
+}